@@ -0,0 +1,100 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+func (c *AccountController) RegisterAdminOn(baseRoute *echo.Group) {
+	baseRoute.GET("/deleted", c.GetDeletedAccounts)
+	baseRoute.GET("/:id", c.InspectAccount)
+	baseRoute.POST("/:id/reopen", c.ReopenAccount)
+	baseRoute.POST("/:id/activate", c.ActivateAccount)
+	baseRoute.POST("/:id/freeze", c.FreezeAccount)
+	baseRoute.POST("/:id/unfreeze", c.UnfreezeAccount)
+}
+
+func (c *AccountController) GetDeletedAccounts(ctx echo.Context) error {
+	ids, err := c.service.GetDeletedAccountIds(ctx.Request().Context())
+	if err != nil {
+		return domainError(err)
+	}
+	return ctx.JSON(http.StatusOK, getAccountsResponse{AccountIds: ids})
+}
+
+func (c *AccountController) InspectAccount(ctx echo.Context) error {
+	id, err := c.getAccountId(ctx)
+	if err != nil {
+		return err
+	}
+	acc, err := c.service.LoadAccount(ctx.Request().Context(), id)
+	if err != nil {
+		return domainError(err)
+	}
+	return ctx.JSON(http.StatusOK, toAccountResponse(&acc))
+}
+
+func (c *AccountController) ReopenAccount(ctx echo.Context) error {
+	id, err := c.getAccountId(ctx)
+	if err != nil {
+		return err
+	}
+	if _, err := c.service.ReopenAccount(ctx.Request().Context(), id); err != nil {
+		return domainError(err)
+	}
+	return ctx.NoContent(http.StatusAccepted)
+}
+
+func (c *AccountController) ActivateAccount(ctx echo.Context) error {
+	id, err := c.getAccountId(ctx)
+	if err != nil {
+		return err
+	}
+	acc, err := c.service.GetAccount(ctx.Request().Context(), id)
+	if err != nil {
+		return domainError(err)
+	}
+	if err := acc.Activate(ctx.Request().Context()); err != nil {
+		return domainError(err)
+	}
+	return ctx.NoContent(http.StatusAccepted)
+}
+
+type freezeRequest struct {
+	Reason string `json:"reason"`
+}
+
+func (c *AccountController) FreezeAccount(ctx echo.Context) error {
+	id, err := c.getAccountId(ctx)
+	if err != nil {
+		return err
+	}
+	body := freezeRequest{}
+	if err := ctx.Bind(&body); err != nil {
+		return badRequest(err, err.Error())
+	}
+	acc, err := c.service.GetAccount(ctx.Request().Context(), id)
+	if err != nil {
+		return domainError(err)
+	}
+	if err := acc.Freeze(ctx.Request().Context(), body.Reason); err != nil {
+		return domainError(err)
+	}
+	return ctx.NoContent(http.StatusAccepted)
+}
+
+func (c *AccountController) UnfreezeAccount(ctx echo.Context) error {
+	id, err := c.getAccountId(ctx)
+	if err != nil {
+		return err
+	}
+	acc, err := c.service.GetAccount(ctx.Request().Context(), id)
+	if err != nil {
+		return domainError(err)
+	}
+	if err := acc.Unfreeze(ctx.Request().Context()); err != nil {
+		return domainError(err)
+	}
+	return ctx.NoContent(http.StatusAccepted)
+}
@@ -0,0 +1,115 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/labstack/echo/v4"
+	"github.com/thomaszub/go-es-example/domain"
+)
+
+type approvalResponse struct {
+	RequestId   gocql.UUID          `json:"requestId"`
+	Command     string              `json:"command"`
+	Amount      *float64            `json:"amount,omitempty"`
+	Limit       *float64            `json:"limit,omitempty"`
+	Details     *transactionDetails `json:"details,omitempty"`
+	RequestedBy string              `json:"requestedBy"`
+	ExpiresAt   time.Time           `json:"expiresAt"`
+}
+
+type getApprovalsResponse struct {
+	Approvals []approvalResponse `json:"approvals"`
+}
+
+type approvalRequiredResponse struct {
+	Message  string           `json:"message"`
+	Approval approvalResponse `json:"approval"`
+}
+
+func approvalRequired(ctx echo.Context, err error) error {
+	if required, ok := err.(*domain.ApprovalRequiredError); ok {
+		return ctx.JSON(http.StatusAccepted, approvalRequiredResponse{Message: required.Error(), Approval: toApprovalResponse(required.Approval)})
+	}
+	return domainError(err)
+}
+
+func (c *AccountController) GetApprovals(ctx echo.Context) error {
+	id, err := c.getAccountId(ctx)
+	if err != nil {
+		return err
+	}
+	acc, err := c.service.AccountFor(ctx.Request().Context(), id, domain.ViewPermission)
+	if err != nil {
+		return domainError(err)
+	}
+	response := getApprovalsResponse{Approvals: []approvalResponse{}}
+	for _, approval := range acc.PendingApprovals() {
+		response.Approvals = append(response.Approvals, toApprovalResponse(approval))
+	}
+	return ctx.JSON(http.StatusOK, response)
+}
+
+func (c *AccountController) ApproveRequest(ctx echo.Context) error {
+	id, err := c.getAccountId(ctx)
+	if err != nil {
+		return err
+	}
+	requestId, err := getUUIDParam(ctx, "requestId")
+	if err != nil {
+		return err
+	}
+	acc, err := c.service.AccountFor(ctx.Request().Context(), id, domain.ViewPermission)
+	if err != nil {
+		return domainError(err)
+	}
+	if err := acc.ApproveRequest(ctx.Request().Context(), requestId); err != nil {
+		return domainError(err)
+	}
+	return ctx.NoContent(http.StatusAccepted)
+}
+
+type rejectRequest struct {
+	Reason string `json:"reason"`
+}
+
+func (c *AccountController) RejectRequest(ctx echo.Context) error {
+	id, err := c.getAccountId(ctx)
+	if err != nil {
+		return err
+	}
+	requestId, err := getUUIDParam(ctx, "requestId")
+	if err != nil {
+		return err
+	}
+	body := rejectRequest{}
+	if err := ctx.Bind(&body); err != nil {
+		return badRequest(err, err.Error())
+	}
+	acc, err := c.service.AccountFor(ctx.Request().Context(), id, domain.ViewPermission)
+	if err != nil {
+		return domainError(err)
+	}
+	if err := acc.RejectRequest(ctx.Request().Context(), requestId, body.Reason); err != nil {
+		return domainError(err)
+	}
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func toApprovalResponse(approval domain.PendingApproval) approvalResponse {
+	r := approvalResponse{
+		RequestId:   approval.RequestId,
+		Command:     string(approval.Command),
+		RequestedBy: approval.RequestedBy,
+		ExpiresAt:   approval.ExpiresAt,
+	}
+	switch approval.Command {
+	case domain.WithdrawApproval:
+		r.Amount = &approval.Amount
+		r.Details = toTransactionDetails(approval.Details)
+	case domain.SetLimitApproval:
+		r.Limit = &approval.Limit
+	}
+	return r
+}
@@ -0,0 +1,95 @@
+package api
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gocql/gocql"
+	"github.com/labstack/echo/v4"
+	"github.com/thomaszub/go-es-example/domain"
+)
+
+type User struct {
+	Name       string
+	Token      string
+	Roles      []string
+	CustomerId gocql.UUID
+}
+
+func ParseUsers(value string) ([]User, error) {
+	var users []User
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.Split(entry, ":")
+		if len(parts) < 2 || len(parts) > 4 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("user %q must have the form name:token[:role|role[:customerId]]", entry)
+		}
+		user := User{Name: parts[0], Token: parts[1]}
+		if len(parts) >= 3 && parts[2] != "" {
+			user.Roles = strings.Split(parts[2], "|")
+		}
+		if len(parts) == 4 {
+			customerId, err := gocql.ParseUUID(parts[3])
+			if err != nil {
+				return nil, fmt.Errorf("customer id of user %s is not valid: %w", parts[0], err)
+			}
+			user.CustomerId = customerId
+		}
+		users = append(users, user)
+	}
+	return users, nil
+}
+
+func Authenticate(users []User) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			if len(users) == 0 {
+				return next(ctx)
+			}
+			token, ok := strings.CutPrefix(ctx.Request().Header.Get(echo.HeaderAuthorization), "Bearer ")
+			if !ok {
+				return echo.NewHTTPError(http.StatusUnauthorized, "a bearer token is required")
+			}
+			user, ok := findUser(users, token)
+			if !ok {
+				return echo.NewHTTPError(http.StatusUnauthorized, "the bearer token is not valid")
+			}
+			request := ctx.Request()
+			ctx.SetRequest(request.WithContext(domain.WithCaller(request.Context(), domain.Caller{
+				Name:       user.Name,
+				Roles:      user.Roles,
+				CustomerId: user.CustomerId,
+			})))
+			return next(ctx)
+		}
+	}
+}
+
+func RequireRole(users []User, role string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			if len(users) == 0 {
+				return next(ctx)
+			}
+			caller := domain.CallerFrom(ctx.Request().Context())
+			if caller.IsCustomer() || !caller.HasRole(role) {
+				return echo.NewHTTPError(http.StatusForbidden, fmt.Sprintf("only staff users with the %s role can access this route", role))
+			}
+			return next(ctx)
+		}
+	}
+}
+
+func findUser(users []User, token string) (User, bool) {
+	for _, user := range users {
+		if subtle.ConstantTimeCompare([]byte(user.Token), []byte(token)) == 1 {
+			return user, true
+		}
+	}
+	return User{}, false
+}
@@ -1,6 +1,8 @@
 package api
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 
@@ -24,9 +26,34 @@ func (c *AccountController) RegisterOn(baseRoute *echo.Group) {
 	baseRoute.POST("", c.CreateAccount)
 	baseRoute.GET("/:id", c.GetAccount)
 	baseRoute.DELETE("/:id", c.DeleteAccount)
+	baseRoute.GET("/:id/events", c.GetAccountEvents)
 	baseRoute.POST("/:id/deposit", c.Deposit)
 	baseRoute.POST("/:id/withdraw", c.Withdraw)
 	baseRoute.PUT("/:id/limit", c.SetLimit)
+	baseRoute.PUT("/:id/policies", c.SetLimitPolicies)
+	baseRoute.PUT("/:id/interest", c.SetInterestTerms)
+	baseRoute.GET("/:id/holds", c.GetHolds)
+	baseRoute.POST("/:id/holds", c.PlaceHold)
+	baseRoute.POST("/:id/holds/:holdId/capture", c.CaptureHold)
+	baseRoute.DELETE("/:id/holds/:holdId", c.ReleaseHold)
+	baseRoute.POST("/:id/transactions/:eventId/reverse", c.ReverseTransaction)
+	baseRoute.POST("/:id/exchange", c.Exchange)
+	baseRoute.GET("/:id/approvals", c.GetApprovals)
+	baseRoute.POST("/:id/approvals/:requestId/approve", c.ApproveRequest)
+	baseRoute.POST("/:id/approvals/:requestId/reject", c.RejectRequest)
+	baseRoute.GET("/:id/holders", c.GetHolders)
+	baseRoute.POST("/:id/holders", c.AddHolder)
+	baseRoute.PUT("/:id/holders/:customerId", c.ChangeHolderPermission)
+	baseRoute.DELETE("/:id/holders/:customerId", c.RemoveHolder)
+	baseRoute.GET("/:id/standing-orders", c.GetStandingOrders)
+	baseRoute.POST("/:id/standing-orders", c.CreateStandingOrder)
+	baseRoute.PUT("/:id/standing-orders/:orderId", c.AmendStandingOrder)
+	baseRoute.DELETE("/:id/standing-orders/:orderId", c.CancelStandingOrder)
+	baseRoute.GET("/:id/mandates", c.GetMandates)
+	baseRoute.POST("/:id/mandates", c.CreateMandate)
+	baseRoute.DELETE("/:id/mandates/:mandateId", c.RevokeMandate)
+	baseRoute.POST("/:id/mandates/:mandateId/collect", c.CollectDirectDebit)
+	baseRoute.POST("/:id/collections/:collectionId/refund", c.RefundDirectDebit)
 }
 
 type getAccountsResponse struct {
@@ -34,53 +61,142 @@ type getAccountsResponse struct {
 }
 
 func (c *AccountController) GetAccounts(ctx echo.Context) error {
-	ids, err := c.service.GetAllAccountIds()
+	ids, err := c.service.GetAccountIdsFor(ctx.Request().Context())
 	if err != nil {
 		return domainError(err)
 	}
 	return ctx.JSON(http.StatusOK, getAccountsResponse{AccountIds: ids})
 }
 
+type newAccountRequest struct {
+	CustomerId     gocql.UUID `json:"customerId"`
+	Type           string     `json:"type"`
+	Currency       string     `json:"currency"`
+	InitialDeposit float64    `json:"initialDeposit"`
+	Limit          float64    `json:"limit"`
+	Pending        bool       `json:"pending"`
+}
+
 type newAccountResponse struct {
-	AccountId gocql.UUID `json:"accountId"`
+	AccountId     gocql.UUID `json:"accountId"`
+	AccountNumber string     `json:"accountNumber,omitempty"`
 }
 
 func (c *AccountController) CreateAccount(ctx echo.Context) error {
-	acc, err := c.service.CreateNewAccount()
+	body := newAccountRequest{}
+	if err := ctx.Bind(&body); err != nil {
+		return badRequest(err, err.Error())
+	}
+	acc, err := c.service.CreateNewAccount(ctx.Request().Context(), domain.NewAccount{
+		CustomerId:     body.CustomerId,
+		Type:           domain.AccountType(body.Type),
+		Currency:       domain.Currency(body.Currency),
+		InitialDeposit: body.InitialDeposit,
+		Limit:          body.Limit,
+		Pending:        body.Pending,
+	})
 	if err != nil {
 		return domainError(err)
 	}
-	return ctx.JSON(http.StatusCreated, newAccountResponse{AccountId: acc.AccountId()})
+	return ctx.JSON(http.StatusCreated, newAccountResponse{AccountId: acc.AccountId(), AccountNumber: string(acc.AccountNumber())})
+}
+
+type limitPolicies struct {
+	MaxDailyWithdrawal   float64 `json:"maxDailyWithdrawal"`
+	MaxHourlyWithdrawals int     `json:"maxHourlyWithdrawals"`
+	MaxTransactionAmount float64 `json:"maxTransactionAmount"`
+}
+
+type accountTypeConstraints struct {
+	Overdraft             bool `json:"overdraft"`
+	Holds                 bool `json:"holds"`
+	MaxMonthlyWithdrawals int  `json:"maxMonthlyWithdrawals,omitempty"`
+	WithdrawalsThisMonth  int  `json:"withdrawalsThisMonth"`
+	WithdrawalApproval    bool `json:"withdrawalApproval"`
 }
 
 type getAccountResponse struct {
-	AccountId gocql.UUID `json:"accountId"`
-	Limit     float64    `json:"limit"`
-	Balance   float64    `json:"balance"`
+	AccountId        gocql.UUID             `json:"accountId"`
+	AccountNumber    string                 `json:"accountNumber,omitempty"`
+	CustomerId       *gocql.UUID            `json:"customerId,omitempty"`
+	Type             string                 `json:"type"`
+	Constraints      accountTypeConstraints `json:"constraints"`
+	State            string                 `json:"state"`
+	Currency         string                 `json:"currency"`
+	Limit            float64                `json:"limit"`
+	Balance          float64                `json:"balance"`
+	AvailableBalance float64                `json:"availableBalance"`
+	Policies         limitPolicies          `json:"policies"`
+	Interest         interestTerms          `json:"interest"`
+	Fees             feeSchedule            `json:"fees"`
 }
 
 func (c *AccountController) GetAccount(ctx echo.Context) error {
-	id, err := getId(ctx)
+	id, err := c.getAccountId(ctx)
 	if err != nil {
 		return err
 	}
-	acc, err := c.service.GetAccount(id)
+	acc, err := c.service.AccountFor(ctx.Request().Context(), id, domain.ViewPermission)
 	if err != nil {
 		return domainError(err)
 	}
-	return ctx.JSON(http.StatusOK, getAccountResponse{
-		AccountId: id,
-		Limit:     acc.Limit(),
-		Balance:   acc.Balance(),
-	})
+	return ctx.JSON(http.StatusOK, toAccountResponse(&acc))
+}
+
+func toAccountResponse(acc *domain.Account) getAccountResponse {
+	profile := acc.Type().Profile()
+	r := getAccountResponse{
+		AccountId:     acc.AccountId(),
+		AccountNumber: string(acc.AccountNumber()),
+		Type:          string(acc.Type()),
+		Constraints: accountTypeConstraints{
+			Overdraft:             profile.Overdraft,
+			Holds:                 profile.Holds,
+			MaxMonthlyWithdrawals: profile.MaxMonthlyWithdrawals,
+			WithdrawalsThisMonth:  acc.WithdrawalsThisMonth(),
+			WithdrawalApproval:    profile.WithdrawalApproval,
+		},
+		State:            string(acc.State()),
+		Currency:         string(acc.Currency()),
+		Limit:            acc.Limit(),
+		Balance:          acc.Balance(),
+		AvailableBalance: acc.AvailableBalance(),
+		Policies:         limitPolicies(acc.LimitPolicies()),
+		Interest: interestTerms{
+			Rate:     acc.InterestTerms().Rate,
+			DayCount: string(acc.InterestTerms().DayCount),
+			Accrued:  acc.AccruedInterest(),
+		},
+		Fees: feeSchedule{
+			Withdrawal:       acc.FeeSchedule().Withdrawal,
+			OverdraftUsage:   acc.FeeSchedule().OverdraftUsage,
+			Maintenance:      acc.FeeSchedule().Maintenance,
+			OverdraftRate:    acc.FeeSchedule().OverdraftRate,
+			AccruedOverdraft: acc.AccruedOverdraftInterest(),
+		},
+	}
+	if customerId := acc.CustomerId(); customerId != (gocql.UUID{}) {
+		r.CustomerId = &customerId
+	}
+	return r
+}
+
+type transactionDetails struct {
+	Reference           string `json:"reference,omitempty"`
+	Description         string `json:"description,omitempty"`
+	CounterpartyName    string `json:"counterpartyName,omitempty"`
+	CounterpartyAccount string `json:"counterpartyAccount,omitempty"`
+	Category            string `json:"category,omitempty"`
 }
 
 type depositRequest struct {
-	Amount float64 `json:"amount"`
+	Amount   float64 `json:"amount"`
+	Currency string  `json:"currency"`
+	transactionDetails
 }
 
 func (c *AccountController) Deposit(ctx echo.Context) error {
-	id, err := getId(ctx)
+	id, err := c.getAccountId(ctx)
 	if err != nil {
 		return err
 	}
@@ -88,22 +204,28 @@ func (c *AccountController) Deposit(ctx echo.Context) error {
 	if err := ctx.Bind(&body); err != nil {
 		return badRequest(err, err.Error())
 	}
-	acc, err := c.service.GetAccount(id)
+	acc, err := c.service.AccountFor(ctx.Request().Context(), id, domain.DepositPermission)
 	if err != nil {
 		return domainError(err)
 	}
-	if err := acc.Deposit(body.Amount); err != nil {
+	currency, err := requestCurrency(&acc, body.Currency)
+	if err != nil {
+		return domainError(err)
+	}
+	if err := acc.Deposit(ctx.Request().Context(), body.Amount, currency, domain.TransactionDetails(body.transactionDetails)); err != nil {
 		return domainError(err)
 	}
 	return ctx.NoContent(http.StatusAccepted)
 }
 
 type withdrawRequest struct {
-	Amount float64 `json:"amount"`
+	Amount   float64 `json:"amount"`
+	Currency string  `json:"currency"`
+	transactionDetails
 }
 
 func (c *AccountController) Withdraw(ctx echo.Context) error {
-	id, err := getId(ctx)
+	id, err := c.getAccountId(ctx)
 	if err != nil {
 		return err
 	}
@@ -111,11 +233,42 @@ func (c *AccountController) Withdraw(ctx echo.Context) error {
 	if err := ctx.Bind(&body); err != nil {
 		return badRequest(err, err.Error())
 	}
-	acc, err := c.service.GetAccount(id)
+	acc, err := c.service.AccountForWithdrawal(ctx.Request().Context(), id, body.Amount)
 	if err != nil {
 		return domainError(err)
 	}
-	if err := acc.Withdraw(body.Amount); err != nil {
+	currency, err := requestCurrency(&acc, body.Currency)
+	if err != nil {
+		return domainError(err)
+	}
+	if err := acc.Withdraw(ctx.Request().Context(), body.Amount, currency, domain.TransactionDetails(body.transactionDetails)); err != nil {
+		return approvalRequired(ctx, err)
+	}
+	return ctx.NoContent(http.StatusAccepted)
+}
+
+type reverseTransactionRequest struct {
+	Reason string `json:"reason"`
+}
+
+func (c *AccountController) ReverseTransaction(ctx echo.Context) error {
+	id, err := c.getAccountId(ctx)
+	if err != nil {
+		return err
+	}
+	eventId, err := getUUIDParam(ctx, "eventId")
+	if err != nil {
+		return err
+	}
+	body := reverseTransactionRequest{}
+	if err := ctx.Bind(&body); err != nil {
+		return badRequest(err, err.Error())
+	}
+	acc, err := c.service.AccountFor(ctx.Request().Context(), id, domain.FullPermission)
+	if err != nil {
+		return domainError(err)
+	}
+	if err := acc.ReverseTransaction(ctx.Request().Context(), eventId, body.Reason); err != nil {
 		return domainError(err)
 	}
 	return ctx.NoContent(http.StatusAccepted)
@@ -126,7 +279,7 @@ type setLimitRequest struct {
 }
 
 func (c *AccountController) SetLimit(ctx echo.Context) error {
-	id, err := getId(ctx)
+	id, err := c.getAccountId(ctx)
 	if err != nil {
 		return err
 	}
@@ -134,18 +287,49 @@ func (c *AccountController) SetLimit(ctx echo.Context) error {
 	if err := ctx.Bind(&body); err != nil {
 		return badRequest(err, err.Error())
 	}
-	acc, err := c.service.GetAccount(id)
+	acc, err := c.service.AccountFor(ctx.Request().Context(), id, domain.FullPermission)
 	if err != nil {
 		return domainError(err)
 	}
-	if err := acc.SetNewLimit(body.Limit); err != nil {
+	if err := acc.SetNewLimit(ctx.Request().Context(), body.Limit); err != nil {
+		return approvalRequired(ctx, err)
+	}
+	return ctx.NoContent(http.StatusAccepted)
+}
+
+func (c *AccountController) SetLimitPolicies(ctx echo.Context) error {
+	id, err := c.getAccountId(ctx)
+	if err != nil {
+		return err
+	}
+	body := limitPolicies{}
+	if err := ctx.Bind(&body); err != nil {
+		return badRequest(err, err.Error())
+	}
+	acc, err := c.service.AccountFor(ctx.Request().Context(), id, domain.FullPermission)
+	if err != nil {
+		return domainError(err)
+	}
+	if err := acc.SetLimitPolicies(ctx.Request().Context(), domain.LimitPolicies(body)); err != nil {
 		return domainError(err)
 	}
 	return ctx.NoContent(http.StatusAccepted)
 }
 
+func (c *AccountController) getAccountId(ctx echo.Context) (gocql.UUID, error) {
+	id, err := c.service.ResolveAccountId(ctx.Request().Context(), ctx.Param("id"))
+	if err != nil {
+		return gocql.UUID{}, domainError(err)
+	}
+	return id, nil
+}
+
 func getId(ctx echo.Context) (gocql.UUID, error) {
-	idString := ctx.Param("id")
+	return getUUIDParam(ctx, "id")
+}
+
+func getUUIDParam(ctx echo.Context, name string) (gocql.UUID, error) {
+	idString := ctx.Param(name)
 	id, err := gocql.ParseUUID(idString)
 	if err != nil {
 		return gocql.UUID{}, badRequest(err, fmt.Sprintf("%s is not a valid id", idString))
@@ -161,13 +345,41 @@ func badRequest(err error, message string) *echo.HTTPError {
 	}
 }
 
+type balanceRemainingResponse struct {
+	Message          string  `json:"message"`
+	RemainingBalance float64 `json:"remainingBalance"`
+}
+
+func requestCurrency(acc *domain.Account, code string) (domain.Currency, error) {
+	if code == "" {
+		return acc.Currency(), nil
+	}
+	return domain.ParseCurrency(code)
+}
+
 func domainError(err error) *echo.HTTPError {
 	code := http.StatusInternalServerError
-	switch err.(type) {
-	case *domain.AccountNotFoundError:
+	switch e := err.(type) {
+	case *domain.AccountNotFoundError, *domain.CustomerNotFoundError:
 		code = http.StatusNotFound
 	case *domain.DomainError:
 		code = http.StatusBadRequest
+	case *domain.RuleDecisionError:
+		code = http.StatusForbidden
+		if e.Outcome == domain.RuleReview {
+			code = http.StatusUnprocessableEntity
+		}
+	case *domain.PermissionDeniedError:
+		code = http.StatusForbidden
+	case *domain.BalanceRemainingError:
+		return &echo.HTTPError{
+			Code:     http.StatusConflict,
+			Message:  balanceRemainingResponse{Message: e.Error(), RemainingBalance: e.Balance},
+			Internal: err,
+		}
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		code = http.StatusGatewayTimeout
 	}
 	return &echo.HTTPError{
 		Code:     code,
@@ -177,15 +389,15 @@ func domainError(err error) *echo.HTTPError {
 }
 
 func (c *AccountController) DeleteAccount(ctx echo.Context) error {
-	id, err := getId(ctx)
+	id, err := c.getAccountId(ctx)
 	if err != nil {
 		return err
 	}
-	acc, err := c.service.GetAccount(id)
+	acc, err := c.service.AccountFor(ctx.Request().Context(), id, domain.FullPermission)
 	if err != nil {
 		return domainError(err)
 	}
-	err = acc.Delete()
+	err = acc.Close(ctx.Request().Context(), ctx.QueryParam("payoutDestination"))
 	if err != nil {
 		return domainError(err)
 	}
@@ -0,0 +1,155 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gocql/gocql"
+	"github.com/labstack/echo/v4"
+	"github.com/thomaszub/go-es-example/domain"
+)
+
+type CustomerController struct {
+	customers *domain.CustomerService
+	accounts  *domain.AccountService
+}
+
+func NewCustomerController(customers *domain.CustomerService, accounts *domain.AccountService) CustomerController {
+	return CustomerController{
+		customers: customers,
+		accounts:  accounts,
+	}
+}
+
+func (c *CustomerController) RegisterOn(baseRoute *echo.Group) {
+	baseRoute.POST("", c.CreateCustomer)
+	baseRoute.GET("/:id", c.GetCustomer)
+	baseRoute.PUT("/:id/name", c.ChangeName)
+	baseRoute.PUT("/:id/address", c.ChangeAddress)
+	baseRoute.PUT("/:id/kyc", c.ChangeKycStatus)
+	baseRoute.GET("/:id/accounts", c.GetCustomerAccounts)
+}
+
+type address struct {
+	Street     string `json:"street"`
+	PostalCode string `json:"postalCode"`
+	City       string `json:"city"`
+	Country    string `json:"country"`
+}
+
+type newCustomerRequest struct {
+	Name    string  `json:"name"`
+	Address address `json:"address"`
+}
+
+type newCustomerResponse struct {
+	CustomerId gocql.UUID `json:"customerId"`
+}
+
+func (c *CustomerController) CreateCustomer(ctx echo.Context) error {
+	body := newCustomerRequest{}
+	if err := ctx.Bind(&body); err != nil {
+		return badRequest(err, err.Error())
+	}
+	customer, err := c.customers.CreateCustomer(ctx.Request().Context(), domain.NewCustomer{
+		Name:    body.Name,
+		Address: domain.Address(body.Address),
+	})
+	if err != nil {
+		return domainError(err)
+	}
+	return ctx.JSON(http.StatusCreated, newCustomerResponse{CustomerId: customer.CustomerId()})
+}
+
+type getCustomerResponse struct {
+	CustomerId gocql.UUID   `json:"customerId"`
+	Name       string       `json:"name"`
+	Address    address      `json:"address"`
+	KycStatus  string       `json:"kycStatus"`
+	AccountIds []gocql.UUID `json:"accountIds"`
+}
+
+func (c *CustomerController) GetCustomer(ctx echo.Context) error {
+	id, err := getId(ctx)
+	if err != nil {
+		return err
+	}
+	customer, err := c.customers.CustomerFor(ctx.Request().Context(), id)
+	if err != nil {
+		return domainError(err)
+	}
+	return ctx.JSON(http.StatusOK, getCustomerResponse{
+		CustomerId: customer.CustomerId(),
+		Name:       customer.Name(),
+		Address:    address(customer.Address()),
+		KycStatus:  string(customer.KycStatus()),
+		AccountIds: customer.Accounts(),
+	})
+}
+
+type changeNameRequest struct {
+	Name string `json:"name"`
+}
+
+func (c *CustomerController) ChangeName(ctx echo.Context) error {
+	body := changeNameRequest{}
+	return c.changeCustomer(ctx, &body, func(customer *domain.Customer) error {
+		return customer.ChangeName(ctx.Request().Context(), body.Name)
+	})
+}
+
+func (c *CustomerController) ChangeAddress(ctx echo.Context) error {
+	body := address{}
+	return c.changeCustomer(ctx, &body, func(customer *domain.Customer) error {
+		return customer.ChangeAddress(ctx.Request().Context(), domain.Address(body))
+	})
+}
+
+type changeKycStatusRequest struct {
+	Status string `json:"status"`
+	Reason string `json:"reason"`
+}
+
+func (c *CustomerController) ChangeKycStatus(ctx echo.Context) error {
+	body := changeKycStatusRequest{}
+	return c.changeCustomer(ctx, &body, func(customer *domain.Customer) error {
+		return customer.ChangeKycStatus(ctx.Request().Context(), domain.KycStatus(body.Status), body.Reason)
+	})
+}
+
+type getCustomerAccountsResponse struct {
+	Accounts []getAccountResponse `json:"accounts"`
+}
+
+func (c *CustomerController) GetCustomerAccounts(ctx echo.Context) error {
+	id, err := getId(ctx)
+	if err != nil {
+		return err
+	}
+	accounts, err := c.accounts.GetCustomerAccounts(ctx.Request().Context(), id)
+	if err != nil {
+		return domainError(err)
+	}
+	response := getCustomerAccountsResponse{Accounts: make([]getAccountResponse, 0, len(accounts))}
+	for i := range accounts {
+		response.Accounts = append(response.Accounts, toAccountResponse(&accounts[i]))
+	}
+	return ctx.JSON(http.StatusOK, response)
+}
+
+func (c *CustomerController) changeCustomer(ctx echo.Context, body interface{}, change func(customer *domain.Customer) error) error {
+	id, err := getId(ctx)
+	if err != nil {
+		return err
+	}
+	if err := ctx.Bind(body); err != nil {
+		return badRequest(err, err.Error())
+	}
+	customer, err := c.customers.CustomerFor(ctx.Request().Context(), id)
+	if err != nil {
+		return domainError(err)
+	}
+	if err := change(&customer); err != nil {
+		return domainError(err)
+	}
+	return ctx.NoContent(http.StatusAccepted)
+}
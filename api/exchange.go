@@ -0,0 +1,49 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gocql/gocql"
+	"github.com/labstack/echo/v4"
+)
+
+type exchangeRequest struct {
+	ToAccountId gocql.UUID `json:"toAccountId"`
+	Amount      float64    `json:"amount"`
+}
+
+type exchangeResponse struct {
+	ExchangeId       gocql.UUID `json:"exchangeId"`
+	Debited          float64    `json:"debited"`
+	DebitedCurrency  string     `json:"debitedCurrency"`
+	Credited         float64    `json:"credited"`
+	CreditedCurrency string     `json:"creditedCurrency"`
+	Rate             float64    `json:"rate"`
+	Spread           float64    `json:"spread"`
+	SnapshotId       gocql.UUID `json:"snapshotId"`
+}
+
+func (c *AccountController) Exchange(ctx echo.Context) error {
+	id, err := c.getAccountId(ctx)
+	if err != nil {
+		return err
+	}
+	body := exchangeRequest{}
+	if err := ctx.Bind(&body); err != nil {
+		return badRequest(err, err.Error())
+	}
+	exchange, err := c.service.Exchange(ctx.Request().Context(), id, body.ToAccountId, body.Amount)
+	if err != nil {
+		return domainError(err)
+	}
+	return ctx.JSON(http.StatusCreated, exchangeResponse{
+		ExchangeId:       exchange.ExchangeId,
+		Debited:          exchange.Debited,
+		DebitedCurrency:  string(exchange.Rate.From),
+		Credited:         exchange.Credited,
+		CreditedCurrency: string(exchange.Rate.To),
+		Rate:             exchange.Rate.Rate,
+		Spread:           exchange.Rate.Spread,
+		SnapshotId:       exchange.Rate.SnapshotId,
+	})
+}
@@ -0,0 +1,70 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/thomaszub/go-es-example/fx"
+)
+
+type FxController struct {
+	table *fx.RateTable
+}
+
+func NewFxController(table *fx.RateTable) FxController {
+	return FxController{
+		table: table,
+	}
+}
+
+func (c *FxController) RegisterOn(baseRoute *echo.Group) {
+	baseRoute.GET("/rates", c.GetRates)
+	baseRoute.PUT("/rates/:from/:to", c.SetRate)
+	baseRoute.GET("/snapshots/:snapshotId", c.GetSnapshot)
+}
+
+type getRatesResponse struct {
+	Rates []fx.RateSnapshot `json:"rates"`
+}
+
+func (c *FxController) GetRates(ctx echo.Context) error {
+	rates, err := c.table.Rates(ctx.Request().Context())
+	if err != nil {
+		return domainError(err)
+	}
+	return ctx.JSON(http.StatusOK, getRatesResponse{Rates: rates})
+}
+
+type setRateRequest struct {
+	Rate   float64 `json:"rate"`
+	Spread float64 `json:"spread"`
+}
+
+func (c *FxController) SetRate(ctx echo.Context) error {
+	body := setRateRequest{}
+	if err := ctx.Bind(&body); err != nil {
+		return badRequest(err, err.Error())
+	}
+	snapshot, err := c.table.SetRate(ctx.Request().Context(), fx.RateDefinition{
+		From:   ctx.Param("from"),
+		To:     ctx.Param("to"),
+		Rate:   body.Rate,
+		Spread: body.Spread,
+	})
+	if err != nil {
+		return domainError(err)
+	}
+	return ctx.JSON(http.StatusOK, snapshot)
+}
+
+func (c *FxController) GetSnapshot(ctx echo.Context) error {
+	snapshotId, err := getUUIDParam(ctx, "snapshotId")
+	if err != nil {
+		return err
+	}
+	snapshot, err := c.table.Snapshot(ctx.Request().Context(), snapshotId)
+	if err != nil {
+		return domainError(err)
+	}
+	return ctx.JSON(http.StatusOK, snapshot)
+}
@@ -0,0 +1,422 @@
+package api
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/labstack/echo/v4"
+	"github.com/thomaszub/go-es-example/domain"
+)
+
+const (
+	defaultEventPageSize = 50
+	maxEventPageSize     = 1000
+	dateLayout           = "2006-01-02"
+)
+
+type accountEventResponse struct {
+	EventId           gocql.UUID          `json:"eventId"`
+	Type              string              `json:"type"`
+	Time              time.Time           `json:"time"`
+	Amount            *float64            `json:"amount,omitempty"`
+	Currency          *string             `json:"currency,omitempty"`
+	AccountType       *string             `json:"accountType,omitempty"`
+	CustomerId        *gocql.UUID         `json:"customerId,omitempty"`
+	AccountNumber     *string             `json:"accountNumber,omitempty"`
+	Permission        *string             `json:"permission,omitempty"`
+	WithdrawalLimit   *float64            `json:"withdrawalLimit,omitempty"`
+	Limit             *float64            `json:"limit,omitempty"`
+	Reason            *string             `json:"reason,omitempty"`
+	PayoutDestination *string             `json:"payoutDestination,omitempty"`
+	HoldId            *gocql.UUID         `json:"holdId,omitempty"`
+	ExpiresAt         *time.Time          `json:"expiresAt,omitempty"`
+	Policies          *limitPolicies      `json:"policies,omitempty"`
+	Command           *string             `json:"command,omitempty"`
+	Outcome           *string             `json:"outcome,omitempty"`
+	Rule              *string             `json:"rule,omitempty"`
+	RuleVersion       *int                `json:"ruleVersion,omitempty"`
+	RequestId         *gocql.UUID         `json:"requestId,omitempty"`
+	RequestedBy       *string             `json:"requestedBy,omitempty"`
+	ApprovedBy        *string             `json:"approvedBy,omitempty"`
+	RejectedBy        *string             `json:"rejectedBy,omitempty"`
+	ReversedEventId   *gocql.UUID         `json:"reversedEventId,omitempty"`
+	ReversedBy        *gocql.UUID         `json:"reversedBy,omitempty"`
+	Details           *transactionDetails `json:"details,omitempty"`
+	ExchangeId        *gocql.UUID         `json:"exchangeId,omitempty"`
+	CounterAccountId  *gocql.UUID         `json:"counterAccountId,omitempty"`
+	CounterAmount     *float64            `json:"counterAmount,omitempty"`
+	CounterCurrency   *string             `json:"counterCurrency,omitempty"`
+	Rate              *float64            `json:"rate,omitempty"`
+	Spread            *float64            `json:"spread,omitempty"`
+	SnapshotId        *gocql.UUID         `json:"snapshotId,omitempty"`
+	DayCount          *string             `json:"dayCount,omitempty"`
+	From              *string             `json:"from,omitempty"`
+	Through           *string             `json:"through,omitempty"`
+	Month             *string             `json:"month,omitempty"`
+	Kind              *string             `json:"kind,omitempty"`
+	OrderId           *gocql.UUID         `json:"orderId,omitempty"`
+	Interval          *string             `json:"interval,omitempty"`
+	StartDate         *string             `json:"startDate,omitempty"`
+	EndDate           *string             `json:"endDate,omitempty"`
+	DueDate           *string             `json:"dueDate,omitempty"`
+	NextExecution     *string             `json:"nextExecution,omitempty"`
+	TransactionId     *gocql.UUID         `json:"transactionId,omitempty"`
+	Attempt           *int                `json:"attempt,omitempty"`
+	RetryAt           *time.Time          `json:"retryAt,omitempty"`
+	MandateId         *gocql.UUID         `json:"mandateId,omitempty"`
+	CreditorId        *string             `json:"creditorId,omitempty"`
+	CreditorName      *string             `json:"creditorName,omitempty"`
+	MandateReference  *string             `json:"mandateReference,omitempty"`
+	MaxAmount         *float64            `json:"maxAmount,omitempty"`
+	MaxMonthlyAmount  *float64            `json:"maxMonthlyAmount,omitempty"`
+	CollectionId      *gocql.UUID         `json:"collectionId,omitempty"`
+	DebitAmount       *float64            `json:"debitAmount,omitempty"`
+	DebitRate         *float64            `json:"debitRate,omitempty"`
+}
+
+type getAccountEventsResponse struct {
+	Events        []accountEventResponse `json:"events"`
+	NextPageState string                 `json:"nextPageState,omitempty"`
+}
+
+func (c *AccountController) GetAccountEvents(ctx echo.Context) error {
+	id, err := c.getAccountId(ctx)
+	if err != nil {
+		return err
+	}
+	pageSize, err := getPageSize(ctx)
+	if err != nil {
+		return err
+	}
+	pageState, err := base64.RawURLEncoding.DecodeString(ctx.QueryParam("pageState"))
+	if err != nil {
+		return badRequest(err, "pageState is not valid")
+	}
+	acc, err := c.service.AccountForHistory(ctx.Request().Context(), id)
+	if err != nil {
+		return domainError(err)
+	}
+	page, err := c.service.GetAccountEvents(ctx.Request().Context(), id, pageState, pageSize)
+	if err != nil {
+		return domainError(err)
+	}
+	response := getAccountEventsResponse{
+		Events:        make([]accountEventResponse, 0, len(page.Events)),
+		NextPageState: base64.RawURLEncoding.EncodeToString(page.NextPageState),
+	}
+	for _, event := range page.Events {
+		r := toAccountEventResponse(event)
+		if reversedBy, ok := acc.ReversedBy(event.GetEventId()); ok {
+			r.ReversedBy = &reversedBy
+		}
+		response.Events = append(response.Events, r)
+	}
+	return ctx.JSON(http.StatusOK, response)
+}
+
+func getPageSize(ctx echo.Context) (int, error) {
+	pageSizeString := ctx.QueryParam("pageSize")
+	if pageSizeString == "" {
+		return defaultEventPageSize, nil
+	}
+	pageSize, err := strconv.Atoi(pageSizeString)
+	if err != nil || pageSize <= 0 || pageSize > maxEventPageSize {
+		return 0, badRequest(err, fmt.Sprintf("pageSize must be between 1 and %d", maxEventPageSize))
+	}
+	return pageSize, nil
+}
+
+func toAccountEventResponse(event domain.AccountEvent) accountEventResponse {
+	r := accountEventResponse{
+		EventId: event.GetEventId(),
+		Time:    event.GetEventId().Time(),
+	}
+	switch e := event.(type) {
+	case domain.AccountCreatedEvent:
+		r.Type = "accountCreated"
+		r.Currency = toCurrency(e.Currency)
+		if e.Type != "" {
+			accountType := string(e.Type)
+			r.AccountType = &accountType
+		}
+		if e.CustomerId != (gocql.UUID{}) {
+			r.CustomerId = &e.CustomerId
+		}
+		if e.AccountNumber != "" {
+			accountNumber := string(e.AccountNumber)
+			r.AccountNumber = &accountNumber
+		}
+	case domain.AccountClosedEvent:
+		r.Type = "accountClosed"
+		r.Amount = &e.FinalBalance
+		if e.PayoutDestination != "" {
+			r.PayoutDestination = &e.PayoutDestination
+		}
+	case domain.AccountReopenedEvent:
+		r.Type = "accountReopened"
+	case domain.AccountActivatedEvent:
+		r.Type = "accountActivated"
+	case domain.AccountFrozenEvent:
+		r.Type = "accountFrozen"
+		r.Reason = &e.Reason
+	case domain.AccountUnfrozenEvent:
+		r.Type = "accountUnfrozen"
+	case domain.AccountDeletedEvent:
+		r.Type = "accountDeleted"
+	case domain.MoneyDipositedEvent:
+		r.Type = "moneyDeposited"
+		r.Amount = &e.Amount
+		r.Currency = toCurrency(e.Currency)
+		r.Details = toTransactionDetails(e.Details)
+	case domain.MoneyWithdrawnEvent:
+		r.Type = "moneyWithdrawn"
+		r.Amount = &e.Amount
+		r.Currency = toCurrency(e.Currency)
+		r.Details = toTransactionDetails(e.Details)
+	case domain.LimitSetEvent:
+		r.Type = "limitSet"
+		r.Limit = &e.Limit
+	case domain.LimitPoliciesSetEvent:
+		r.Type = "limitPoliciesSet"
+		policies := limitPolicies(e.Policies)
+		r.Policies = &policies
+	case domain.RuleDecisionRecordedEvent:
+		r.Type = "ruleDecisionRecorded"
+		command := string(e.Command)
+		outcome := string(e.Outcome)
+		r.Command = &command
+		r.Amount = &e.Amount
+		r.Outcome = &outcome
+		if e.Rule != "" {
+			r.Rule = &e.Rule
+			r.RuleVersion = &e.RuleVersion
+		}
+	case domain.ApprovalRequestedEvent:
+		r.Type = "approvalRequested"
+		command := string(e.Command)
+		r.RequestId = &e.RequestId
+		r.Command = &command
+		switch e.Command {
+		case domain.WithdrawApproval:
+			r.Amount = &e.Amount
+		case domain.SetLimitApproval:
+			r.Limit = &e.Limit
+		}
+		r.RequestedBy = &e.RequestedBy
+		r.ExpiresAt = &e.ExpiresAt
+		r.Details = toTransactionDetails(e.Details)
+	case domain.ApprovalGrantedEvent:
+		r.Type = "approvalGranted"
+		r.RequestId = &e.RequestId
+		r.ApprovedBy = &e.ApprovedBy
+	case domain.ApprovalRejectedEvent:
+		r.Type = "approvalRejected"
+		r.RequestId = &e.RequestId
+		r.RejectedBy = &e.RejectedBy
+		if e.Reason != "" {
+			r.Reason = &e.Reason
+		}
+	case domain.ApprovalExpiredEvent:
+		r.Type = "approvalExpired"
+		r.RequestId = &e.RequestId
+	case domain.TransactionReversedEvent:
+		r.Type = "transactionReversed"
+		command := string(e.Command)
+		r.ReversedEventId = &e.ReversedEventId
+		r.Command = &command
+		r.Amount = &e.Amount
+		if e.Reason != "" {
+			r.Reason = &e.Reason
+		}
+	case domain.ExchangeDebitedEvent:
+		r.Type = "exchangeDebited"
+		withExchange(&r, e.ExchangeId, e.Amount, e.Currency, e.CounterAccountId, e.CounterAmount, e.CounterCurrency, e.Rate, e.Spread, e.SnapshotId)
+	case domain.ExchangeCreditedEvent:
+		r.Type = "exchangeCredited"
+		withExchange(&r, e.ExchangeId, e.Amount, e.Currency, e.CounterAccountId, e.CounterAmount, e.CounterCurrency, e.Rate, e.Spread, e.SnapshotId)
+	case domain.InterestRateSetEvent:
+		r.Type = "interestRateSet"
+		dayCount := string(e.DayCount)
+		r.Rate = &e.Rate
+		r.DayCount = &dayCount
+	case domain.InterestAccruedEvent:
+		r.Type = "interestAccrued"
+		dayCount := string(e.DayCount)
+		from := e.From.Format(dateLayout)
+		through := e.Through.Format(dateLayout)
+		r.From = &from
+		r.Through = &through
+		r.Amount = &e.Amount
+		r.Rate = &e.Rate
+		r.DayCount = &dayCount
+		if e.DebitRate != 0 {
+			r.DebitAmount = &e.DebitAmount
+			r.DebitRate = &e.DebitRate
+		}
+	case domain.InterestCreditedEvent:
+		r.Type = "interestCredited"
+		r.Amount = &e.Amount
+		r.Month = &e.Month
+	case domain.FeeChargedEvent:
+		r.Type = "feeCharged"
+		kind := string(e.Kind)
+		r.Kind = &kind
+		r.Amount = &e.Amount
+		r.Currency = toCurrency(e.Currency)
+		if e.Month != "" {
+			r.Month = &e.Month
+		}
+	case domain.HolderAddedEvent:
+		r.Type = "holderAdded"
+		withHolder(&r, e.CustomerId, e.Permission, e.WithdrawalLimit)
+	case domain.HolderPermissionChangedEvent:
+		r.Type = "holderPermissionChanged"
+		withHolder(&r, e.CustomerId, e.Permission, e.WithdrawalLimit)
+	case domain.HolderRemovedEvent:
+		r.Type = "holderRemoved"
+		r.CustomerId = &e.CustomerId
+	case domain.StandingOrderCreatedEvent:
+		r.Type = "standingOrderCreated"
+		interval := string(e.Interval)
+		r.OrderId = &e.OrderId
+		r.Amount = &e.Amount
+		r.Interval = &interval
+		r.StartDate = toDate(e.StartDate)
+		r.EndDate = toDate(e.EndDate)
+		r.Details = toTransactionDetails(e.Details)
+		if e.ToAccountId != (gocql.UUID{}) {
+			r.CounterAccountId = &e.ToAccountId
+		}
+	case domain.StandingOrderAmendedEvent:
+		r.Type = "standingOrderAmended"
+		interval := string(e.Interval)
+		r.OrderId = &e.OrderId
+		r.Amount = &e.Amount
+		r.Interval = &interval
+		r.NextExecution = toDate(e.NextExecution)
+		r.EndDate = toDate(e.EndDate)
+		r.Details = toTransactionDetails(e.Details)
+	case domain.StandingOrderCancelledEvent:
+		r.Type = "standingOrderCancelled"
+		r.OrderId = &e.OrderId
+		if e.Reason != "" {
+			r.Reason = &e.Reason
+		}
+	case domain.StandingOrderExecutedEvent:
+		r.Type = "standingOrderExecuted"
+		r.OrderId = &e.OrderId
+		r.Amount = &e.Amount
+		r.DueDate = toDate(e.DueDate)
+		r.TransactionId = &e.TransactionId
+		r.NextExecution = toDate(e.NextExecution)
+	case domain.StandingOrderFailedEvent:
+		r.Type = "standingOrderFailed"
+		r.OrderId = &e.OrderId
+		r.DueDate = toDate(e.DueDate)
+		r.Attempt = &e.Attempt
+		r.Reason = &e.Reason
+		r.NextExecution = toDate(e.NextExecution)
+		if !e.RetryAt.IsZero() {
+			r.RetryAt = &e.RetryAt
+		}
+	case domain.MandateCreatedEvent:
+		r.Type = "mandateCreated"
+		r.MandateId = &e.MandateId
+		r.CreditorId = &e.CreditorId
+		r.CreditorName = &e.CreditorName
+		r.MandateReference = &e.Reference
+		if e.MaxAmount > 0 {
+			r.MaxAmount = &e.MaxAmount
+		}
+		if e.MaxMonthlyAmount > 0 {
+			r.MaxMonthlyAmount = &e.MaxMonthlyAmount
+		}
+	case domain.MandateRevokedEvent:
+		r.Type = "mandateRevoked"
+		r.MandateId = &e.MandateId
+		if e.Reason != "" {
+			r.Reason = &e.Reason
+		}
+	case domain.DirectDebitCollectedEvent:
+		r.Type = "directDebitCollected"
+		r.MandateId = &e.MandateId
+		r.Amount = &e.Amount
+		r.Currency = toCurrency(e.Currency)
+		r.Details = toTransactionDetails(e.Details)
+	case domain.DirectDebitRefundedEvent:
+		r.Type = "directDebitRefunded"
+		r.CollectionId = &e.CollectionId
+		r.MandateId = &e.MandateId
+		r.Amount = &e.Amount
+		if e.Reason != "" {
+			r.Reason = &e.Reason
+		}
+	case domain.HoldPlacedEvent:
+		r.Type = "holdPlaced"
+		r.HoldId = &e.HoldId
+		r.Amount = &e.Amount
+		r.ExpiresAt = &e.ExpiresAt
+	case domain.HoldCapturedEvent:
+		r.Type = "holdCaptured"
+		r.HoldId = &e.HoldId
+		r.Amount = &e.Amount
+	case domain.HoldReleasedEvent:
+		r.Type = "holdReleased"
+		r.HoldId = &e.HoldId
+	case domain.HoldExpiredEvent:
+		r.Type = "holdExpired"
+		r.HoldId = &e.HoldId
+	default:
+		r.Type = "unknown"
+	}
+	return r
+}
+
+func toTransactionDetails(details domain.TransactionDetails) *transactionDetails {
+	if details.IsZero() {
+		return nil
+	}
+	r := transactionDetails(details)
+	return &r
+}
+
+func toDate(date time.Time) *string {
+	if date.IsZero() {
+		return nil
+	}
+	formatted := date.Format(dateLayout)
+	return &formatted
+}
+
+func toCurrency(currency domain.Currency) *string {
+	if currency == "" {
+		return nil
+	}
+	code := string(currency)
+	return &code
+}
+
+func withExchange(r *accountEventResponse, exchangeId gocql.UUID, amount float64, currency domain.Currency, counterAccountId gocql.UUID, counterAmount float64, counterCurrency domain.Currency, rate, spread float64, snapshotId gocql.UUID) {
+	r.ExchangeId = &exchangeId
+	r.Amount = &amount
+	r.Currency = toCurrency(currency)
+	r.CounterAccountId = &counterAccountId
+	r.CounterAmount = &counterAmount
+	r.CounterCurrency = toCurrency(counterCurrency)
+	r.Rate = &rate
+	r.Spread = &spread
+	r.SnapshotId = &snapshotId
+}
+
+func withHolder(r *accountEventResponse, customerId gocql.UUID, permission domain.HolderPermission, withdrawalLimit float64) {
+	p := string(permission)
+	r.CustomerId = &customerId
+	r.Permission = &p
+	if withdrawalLimit > 0 {
+		r.WithdrawalLimit = &withdrawalLimit
+	}
+}
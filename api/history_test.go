@@ -0,0 +1,72 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gocql/gocql"
+	"github.com/labstack/echo/v4"
+	"github.com/thomaszub/go-es-example/database"
+	"github.com/thomaszub/go-es-example/domain"
+)
+
+func TestGetAccountEventsLinksReversalOnLaterPage(t *testing.T) {
+	repo, err := database.OpenFileRepository(t.TempDir(), database.SegmentLogOptions{FsyncPolicy: database.FsyncNever})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer repo.Close()
+	accountId := gocql.TimeUUID()
+	created := domain.AccountCreatedEvent{AccountId: accountId, EventId: gocql.TimeUUID(), Currency: domain.DefaultCurrency}
+	deposit := domain.MoneyDipositedEvent{AccountId: accountId, EventId: gocql.TimeUUID(), Amount: 100, Currency: domain.DefaultCurrency}
+	reversal := domain.TransactionReversedEvent{
+		AccountId:       accountId,
+		EventId:         gocql.TimeUUID(),
+		ReversedEventId: deposit.EventId,
+		Command:         domain.DepositCommand,
+		Amount:          100,
+	}
+	err = repo.Write(context.Background(),
+		created,
+		deposit,
+		reversal,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	service := domain.NewAccountService(repo, domain.AccountServiceConfig{})
+	controller := NewAccountController(&service)
+
+	first := getEventsPage(t, &controller, accountId, "")
+	if len(first.Events) != 2 || first.Events[1].EventId != deposit.EventId {
+		t.Fatalf("expected the deposit at the end of the first page, got %+v", first.Events)
+	}
+	if first.Events[1].ReversedBy == nil || *first.Events[1].ReversedBy != reversal.EventId {
+		t.Fatalf("expected the deposit to be reversed by %s, got %v", reversal.EventId, first.Events[1].ReversedBy)
+	}
+	second := getEventsPage(t, &controller, accountId, first.NextPageState)
+	if len(second.Events) != 1 || second.Events[0].EventId != reversal.EventId {
+		t.Fatalf("expected the reversal on the second page, got %+v", second.Events)
+	}
+}
+
+func getEventsPage(t *testing.T, controller *AccountController, accountId gocql.UUID, pageState string) getAccountEventsResponse {
+	t.Helper()
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/?pageSize=2&pageState="+pageState, nil)
+	rec := httptest.NewRecorder()
+	ctx := e.NewContext(req, rec)
+	ctx.SetParamNames("id")
+	ctx.SetParamValues(accountId.String())
+	if err := controller.GetAccountEvents(ctx); err != nil {
+		t.Fatal(err)
+	}
+	response := getAccountEventsResponse{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+	return response
+}
@@ -0,0 +1,104 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gocql/gocql"
+	"github.com/labstack/echo/v4"
+	"github.com/thomaszub/go-es-example/domain"
+)
+
+type holderResponse struct {
+	CustomerId      gocql.UUID `json:"customerId"`
+	Permission      string     `json:"permission"`
+	WithdrawalLimit float64    `json:"withdrawalLimit,omitempty"`
+}
+
+type getHoldersResponse struct {
+	Holders []holderResponse `json:"holders"`
+}
+
+func (c *AccountController) GetHolders(ctx echo.Context) error {
+	id, err := c.getAccountId(ctx)
+	if err != nil {
+		return err
+	}
+	acc, err := c.service.AccountFor(ctx.Request().Context(), id, domain.ViewPermission)
+	if err != nil {
+		return domainError(err)
+	}
+	response := getHoldersResponse{Holders: []holderResponse{}}
+	for _, holder := range acc.Holders() {
+		response.Holders = append(response.Holders, holderResponse{
+			CustomerId:      holder.CustomerId,
+			Permission:      string(holder.Permission),
+			WithdrawalLimit: holder.WithdrawalLimit,
+		})
+	}
+	return ctx.JSON(http.StatusOK, response)
+}
+
+type holderRequest struct {
+	CustomerId      gocql.UUID `json:"customerId"`
+	Permission      string     `json:"permission"`
+	WithdrawalLimit float64    `json:"withdrawalLimit"`
+}
+
+func (c *AccountController) AddHolder(ctx echo.Context) error {
+	id, err := c.getAccountId(ctx)
+	if err != nil {
+		return err
+	}
+	body := holderRequest{}
+	if err := ctx.Bind(&body); err != nil {
+		return badRequest(err, err.Error())
+	}
+	err = c.service.AddHolder(ctx.Request().Context(), id, domain.Holder{
+		CustomerId:      body.CustomerId,
+		Permission:      domain.HolderPermission(body.Permission),
+		WithdrawalLimit: body.WithdrawalLimit,
+	})
+	if err != nil {
+		return domainError(err)
+	}
+	return ctx.NoContent(http.StatusCreated)
+}
+
+func (c *AccountController) ChangeHolderPermission(ctx echo.Context) error {
+	id, err := c.getAccountId(ctx)
+	if err != nil {
+		return err
+	}
+	customerId, err := getUUIDParam(ctx, "customerId")
+	if err != nil {
+		return err
+	}
+	body := holderRequest{}
+	if err := ctx.Bind(&body); err != nil {
+		return badRequest(err, err.Error())
+	}
+	err = c.service.ChangeHolderPermission(ctx.Request().Context(), id, domain.Holder{
+		CustomerId:      customerId,
+		Permission:      domain.HolderPermission(body.Permission),
+		WithdrawalLimit: body.WithdrawalLimit,
+	})
+	if err != nil {
+		return domainError(err)
+	}
+	return ctx.NoContent(http.StatusAccepted)
+}
+
+func (c *AccountController) RemoveHolder(ctx echo.Context) error {
+	id, err := c.getAccountId(ctx)
+	if err != nil {
+		return err
+	}
+	customerId, err := getUUIDParam(ctx, "customerId")
+	if err != nil {
+		return err
+	}
+	if err := c.service.RemoveHolder(ctx.Request().Context(), id, customerId); err != nil {
+		return domainError(err)
+	}
+	return ctx.NoContent(http.StatusAccepted)
+}
@@ -0,0 +1,115 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/labstack/echo/v4"
+	"github.com/thomaszub/go-es-example/domain"
+)
+
+type holdResponse struct {
+	HoldId    gocql.UUID `json:"holdId"`
+	Amount    float64    `json:"amount"`
+	ExpiresAt time.Time  `json:"expiresAt"`
+}
+
+type getHoldsResponse struct {
+	Holds []holdResponse `json:"holds"`
+}
+
+func (c *AccountController) GetHolds(ctx echo.Context) error {
+	id, err := c.getAccountId(ctx)
+	if err != nil {
+		return err
+	}
+	acc, err := c.service.AccountFor(ctx.Request().Context(), id, domain.ViewPermission)
+	if err != nil {
+		return domainError(err)
+	}
+	response := getHoldsResponse{Holds: []holdResponse{}}
+	for _, hold := range acc.Holds() {
+		response.Holds = append(response.Holds, toHoldResponse(hold))
+	}
+	return ctx.JSON(http.StatusOK, response)
+}
+
+type placeHoldRequest struct {
+	Amount    float64   `json:"amount"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+func (c *AccountController) PlaceHold(ctx echo.Context) error {
+	id, err := c.getAccountId(ctx)
+	if err != nil {
+		return err
+	}
+	body := placeHoldRequest{}
+	if err := ctx.Bind(&body); err != nil {
+		return badRequest(err, err.Error())
+	}
+	acc, err := c.service.AccountForWithdrawal(ctx.Request().Context(), id, body.Amount)
+	if err != nil {
+		return domainError(err)
+	}
+	hold, err := acc.PlaceHold(ctx.Request().Context(), body.Amount, body.ExpiresAt)
+	if err != nil {
+		return domainError(err)
+	}
+	return ctx.JSON(http.StatusCreated, toHoldResponse(hold))
+}
+
+type captureHoldRequest struct {
+	Amount float64 `json:"amount"`
+}
+
+func (c *AccountController) CaptureHold(ctx echo.Context) error {
+	id, err := c.getAccountId(ctx)
+	if err != nil {
+		return err
+	}
+	holdId, err := getUUIDParam(ctx, "holdId")
+	if err != nil {
+		return err
+	}
+	body := captureHoldRequest{}
+	if err := ctx.Bind(&body); err != nil {
+		return badRequest(err, err.Error())
+	}
+	acc, err := c.service.AccountFor(ctx.Request().Context(), id, domain.WithdrawPermission)
+	if err != nil {
+		return domainError(err)
+	}
+	if err := acc.CaptureHold(ctx.Request().Context(), holdId, body.Amount); err != nil {
+		return domainError(err)
+	}
+	return ctx.NoContent(http.StatusAccepted)
+}
+
+func (c *AccountController) ReleaseHold(ctx echo.Context) error {
+	id, err := c.getAccountId(ctx)
+	if err != nil {
+		return err
+	}
+	holdId, err := getUUIDParam(ctx, "holdId")
+	if err != nil {
+		return err
+	}
+	acc, err := c.service.AccountFor(ctx.Request().Context(), id, domain.WithdrawPermission)
+	if err != nil {
+		return domainError(err)
+	}
+	if err := acc.ReleaseHold(ctx.Request().Context(), holdId); err != nil {
+		return domainError(err)
+	}
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func toHoldResponse(hold domain.Hold) holdResponse {
+	return holdResponse{
+		HoldId:    hold.HoldId,
+		Amount:    hold.Amount,
+		ExpiresAt: hold.ExpiresAt,
+	}
+}
@@ -0,0 +1,50 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/thomaszub/go-es-example/domain"
+)
+
+type interestTerms struct {
+	Rate     float64 `json:"rate"`
+	DayCount string  `json:"dayCount,omitempty"`
+	Accrued  float64 `json:"accrued"`
+}
+
+type feeSchedule struct {
+	Withdrawal       float64 `json:"withdrawal"`
+	OverdraftUsage   float64 `json:"overdraftUsage"`
+	Maintenance      float64 `json:"maintenance"`
+	OverdraftRate    float64 `json:"overdraftRate"`
+	AccruedOverdraft float64 `json:"accruedOverdraft"`
+}
+
+type setInterestTermsRequest struct {
+	Rate     float64 `json:"rate"`
+	DayCount string  `json:"dayCount"`
+}
+
+func (c *AccountController) SetInterestTerms(ctx echo.Context) error {
+	id, err := c.getAccountId(ctx)
+	if err != nil {
+		return err
+	}
+	body := setInterestTermsRequest{}
+	if err := ctx.Bind(&body); err != nil {
+		return badRequest(err, err.Error())
+	}
+	acc, err := c.service.AccountFor(ctx.Request().Context(), id, domain.FullPermission)
+	if err != nil {
+		return domainError(err)
+	}
+	err = acc.SetInterestTerms(ctx.Request().Context(), domain.InterestTerms{
+		Rate:     body.Rate,
+		DayCount: domain.DayCountConvention(body.DayCount),
+	})
+	if err != nil {
+		return domainError(err)
+	}
+	return ctx.NoContent(http.StatusAccepted)
+}
@@ -0,0 +1,184 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/labstack/echo/v4"
+	"github.com/thomaszub/go-es-example/domain"
+)
+
+type mandateResponse struct {
+	MandateId          gocql.UUID `json:"mandateId"`
+	CreditorId         string     `json:"creditorId"`
+	CreditorName       string     `json:"creditorName"`
+	Reference          string     `json:"reference"`
+	MaxAmount          float64    `json:"maxAmount,omitempty"`
+	MaxMonthlyAmount   float64    `json:"maxMonthlyAmount,omitempty"`
+	CollectedThisMonth float64    `json:"collectedThisMonth"`
+	SignedAt           time.Time  `json:"signedAt"`
+}
+
+type getMandatesResponse struct {
+	Mandates []mandateResponse `json:"mandates"`
+}
+
+func (c *AccountController) GetMandates(ctx echo.Context) error {
+	id, err := c.getAccountId(ctx)
+	if err != nil {
+		return err
+	}
+	acc, err := c.service.AccountFor(ctx.Request().Context(), id, domain.ViewPermission)
+	if err != nil {
+		return domainError(err)
+	}
+	response := getMandatesResponse{Mandates: []mandateResponse{}}
+	for _, mandate := range acc.Mandates() {
+		response.Mandates = append(response.Mandates, toMandateResponse(&acc, mandate))
+	}
+	return ctx.JSON(http.StatusOK, response)
+}
+
+type createMandateRequest struct {
+	CreditorId       string  `json:"creditorId"`
+	CreditorName     string  `json:"creditorName"`
+	Reference        string  `json:"reference"`
+	MaxAmount        float64 `json:"maxAmount"`
+	MaxMonthlyAmount float64 `json:"maxMonthlyAmount"`
+}
+
+func (c *AccountController) CreateMandate(ctx echo.Context) error {
+	id, err := c.getAccountId(ctx)
+	if err != nil {
+		return err
+	}
+	body := createMandateRequest{}
+	if err := ctx.Bind(&body); err != nil {
+		return badRequest(err, err.Error())
+	}
+	acc, err := c.service.AccountFor(ctx.Request().Context(), id, domain.FullPermission)
+	if err != nil {
+		return domainError(err)
+	}
+	mandate, err := acc.CreateMandate(ctx.Request().Context(), domain.Mandate{
+		CreditorId:       body.CreditorId,
+		CreditorName:     body.CreditorName,
+		Reference:        body.Reference,
+		MaxAmount:        body.MaxAmount,
+		MaxMonthlyAmount: body.MaxMonthlyAmount,
+	})
+	if err != nil {
+		return domainError(err)
+	}
+	return ctx.JSON(http.StatusCreated, toMandateResponse(&acc, mandate))
+}
+
+type revokeMandateRequest struct {
+	Reason string `json:"reason"`
+}
+
+func (c *AccountController) RevokeMandate(ctx echo.Context) error {
+	id, err := c.getAccountId(ctx)
+	if err != nil {
+		return err
+	}
+	mandateId, err := getUUIDParam(ctx, "mandateId")
+	if err != nil {
+		return err
+	}
+	body := revokeMandateRequest{}
+	if err := ctx.Bind(&body); err != nil {
+		return badRequest(err, err.Error())
+	}
+	acc, err := c.service.AccountFor(ctx.Request().Context(), id, domain.FullPermission)
+	if err != nil {
+		return domainError(err)
+	}
+	if err := acc.RevokeMandate(ctx.Request().Context(), mandateId, body.Reason); err != nil {
+		return domainError(err)
+	}
+	return ctx.NoContent(http.StatusAccepted)
+}
+
+type collectDirectDebitRequest struct {
+	Amount   float64 `json:"amount"`
+	Currency string  `json:"currency"`
+	transactionDetails
+}
+
+type collectionResponse struct {
+	CollectionId gocql.UUID `json:"collectionId"`
+	MandateId    gocql.UUID `json:"mandateId"`
+	Amount       float64    `json:"amount"`
+	CollectedAt  time.Time  `json:"collectedAt"`
+}
+
+func (c *AccountController) CollectDirectDebit(ctx echo.Context) error {
+	id, err := c.getAccountId(ctx)
+	if err != nil {
+		return err
+	}
+	mandateId, err := getUUIDParam(ctx, "mandateId")
+	if err != nil {
+		return err
+	}
+	body := collectDirectDebitRequest{}
+	if err := ctx.Bind(&body); err != nil {
+		return badRequest(err, err.Error())
+	}
+	acc, err := c.service.GetAccount(ctx.Request().Context(), id)
+	if err != nil {
+		return domainError(err)
+	}
+	currency, err := requestCurrency(&acc, body.Currency)
+	if err != nil {
+		return domainError(err)
+	}
+	collection, err := acc.CollectDirectDebit(ctx.Request().Context(), mandateId, body.Amount, currency, domain.TransactionDetails(body.transactionDetails))
+	if err != nil {
+		return domainError(err)
+	}
+	return ctx.JSON(http.StatusCreated, collectionResponse{
+		CollectionId: collection.CollectionId,
+		MandateId:    collection.MandateId,
+		Amount:       collection.Amount,
+		CollectedAt:  collection.CollectedAt,
+	})
+}
+
+type refundDirectDebitRequest struct {
+	Reason string `json:"reason"`
+}
+
+func (c *AccountController) RefundDirectDebit(ctx echo.Context) error {
+	id, err := c.getAccountId(ctx)
+	if err != nil {
+		return err
+	}
+	collectionId, err := getUUIDParam(ctx, "collectionId")
+	if err != nil {
+		return err
+	}
+	body := refundDirectDebitRequest{}
+	if err := ctx.Bind(&body); err != nil {
+		return badRequest(err, err.Error())
+	}
+	if err := c.service.RefundDirectDebit(ctx.Request().Context(), id, collectionId, body.Reason); err != nil {
+		return domainError(err)
+	}
+	return ctx.NoContent(http.StatusAccepted)
+}
+
+func toMandateResponse(acc *domain.Account, mandate domain.Mandate) mandateResponse {
+	return mandateResponse{
+		MandateId:          mandate.MandateId,
+		CreditorId:         mandate.CreditorId,
+		CreditorName:       mandate.CreditorName,
+		Reference:          mandate.Reference,
+		MaxAmount:          mandate.MaxAmount,
+		MaxMonthlyAmount:   mandate.MaxMonthlyAmount,
+		CollectedThisMonth: acc.CollectedThisMonth(mandate.MandateId),
+		SignedAt:           mandate.SignedAt,
+	}
+}
@@ -0,0 +1,92 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gocql/gocql"
+	"github.com/labstack/echo/v4"
+	"github.com/thomaszub/go-es-example/domain"
+	"github.com/thomaszub/go-es-example/rules"
+)
+
+type RuleController struct {
+	engine *rules.Engine
+}
+
+func NewRuleController(engine *rules.Engine) RuleController {
+	return RuleController{
+		engine: engine,
+	}
+}
+
+func (c *RuleController) RegisterOn(baseRoute *echo.Group) {
+	baseRoute.GET("", c.GetRules)
+	baseRoute.PUT("/:name", c.DefineRule)
+	baseRoute.DELETE("/:name", c.RemoveRule)
+}
+
+type ruleResponse struct {
+	Name        string     `json:"name"`
+	Version     int        `json:"version"`
+	Expression  string     `json:"expression"`
+	Action      string     `json:"action"`
+	Description string     `json:"description"`
+	DefinedAt   gocql.UUID `json:"definedAt"`
+}
+
+type getRulesResponse struct {
+	Rules []ruleResponse `json:"rules"`
+}
+
+func (c *RuleController) GetRules(ctx echo.Context) error {
+	rs, err := c.engine.Rules(ctx.Request().Context())
+	if err != nil {
+		return domainError(err)
+	}
+	response := getRulesResponse{Rules: []ruleResponse{}}
+	for _, rule := range rs {
+		response.Rules = append(response.Rules, toRuleResponse(rule))
+	}
+	return ctx.JSON(http.StatusOK, response)
+}
+
+type defineRuleRequest struct {
+	Expression  string `json:"expression"`
+	Action      string `json:"action"`
+	Description string `json:"description"`
+}
+
+func (c *RuleController) DefineRule(ctx echo.Context) error {
+	body := defineRuleRequest{}
+	if err := ctx.Bind(&body); err != nil {
+		return badRequest(err, err.Error())
+	}
+	rule, err := c.engine.DefineRule(ctx.Request().Context(), rules.RuleDefinition{
+		Name:        ctx.Param("name"),
+		Expression:  body.Expression,
+		Action:      domain.RuleOutcome(body.Action),
+		Description: body.Description,
+	})
+	if err != nil {
+		return domainError(err)
+	}
+	return ctx.JSON(http.StatusOK, toRuleResponse(rule))
+}
+
+func (c *RuleController) RemoveRule(ctx echo.Context) error {
+	if err := c.engine.RemoveRule(ctx.Request().Context(), ctx.Param("name")); err != nil {
+		return domainError(err)
+	}
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func toRuleResponse(rule rules.Rule) ruleResponse {
+	return ruleResponse{
+		Name:        rule.Name,
+		Version:     rule.Version,
+		Expression:  rule.Expression,
+		Action:      string(rule.Action),
+		Description: rule.Description,
+		DefinedAt:   rule.DefinedAt,
+	}
+}
@@ -0,0 +1,184 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/labstack/echo/v4"
+	"github.com/thomaszub/go-es-example/domain"
+)
+
+type standingOrderResponse struct {
+	OrderId       gocql.UUID  `json:"orderId"`
+	ToAccountId   *gocql.UUID `json:"toAccountId,omitempty"`
+	Amount        float64     `json:"amount"`
+	Interval      string      `json:"interval"`
+	StartDate     string      `json:"startDate"`
+	EndDate       *string     `json:"endDate,omitempty"`
+	NextExecution string      `json:"nextExecution"`
+	Attempts      int         `json:"attempts,omitempty"`
+	RetryAt       *time.Time  `json:"retryAt,omitempty"`
+	transactionDetails
+}
+
+type getStandingOrdersResponse struct {
+	StandingOrders []standingOrderResponse `json:"standingOrders"`
+}
+
+func (c *AccountController) GetStandingOrders(ctx echo.Context) error {
+	id, err := c.getAccountId(ctx)
+	if err != nil {
+		return err
+	}
+	acc, err := c.service.AccountFor(ctx.Request().Context(), id, domain.ViewPermission)
+	if err != nil {
+		return domainError(err)
+	}
+	response := getStandingOrdersResponse{StandingOrders: []standingOrderResponse{}}
+	for _, order := range acc.StandingOrders() {
+		response.StandingOrders = append(response.StandingOrders, toStandingOrderResponse(order))
+	}
+	return ctx.JSON(http.StatusOK, response)
+}
+
+type standingOrderRequest struct {
+	ToAccount string  `json:"toAccount"`
+	Amount    float64 `json:"amount"`
+	Interval  string  `json:"interval"`
+	StartDate string  `json:"startDate"`
+	EndDate   string  `json:"endDate"`
+	transactionDetails
+}
+
+func (c *AccountController) CreateStandingOrder(ctx echo.Context) error {
+	id, err := c.getAccountId(ctx)
+	if err != nil {
+		return err
+	}
+	body := standingOrderRequest{}
+	if err := ctx.Bind(&body); err != nil {
+		return badRequest(err, err.Error())
+	}
+	terms, err := c.toStandingOrderTerms(ctx, body)
+	if err != nil {
+		return err
+	}
+	order, err := c.service.CreateStandingOrder(ctx.Request().Context(), id, terms)
+	if err != nil {
+		return domainError(err)
+	}
+	return ctx.JSON(http.StatusCreated, toStandingOrderResponse(order))
+}
+
+func (c *AccountController) AmendStandingOrder(ctx echo.Context) error {
+	id, err := c.getAccountId(ctx)
+	if err != nil {
+		return err
+	}
+	orderId, err := getUUIDParam(ctx, "orderId")
+	if err != nil {
+		return err
+	}
+	body := standingOrderRequest{}
+	if err := ctx.Bind(&body); err != nil {
+		return badRequest(err, err.Error())
+	}
+	terms, err := c.toStandingOrderTerms(ctx, body)
+	if err != nil {
+		return err
+	}
+	acc, err := c.service.AccountFor(ctx.Request().Context(), id, domain.FullPermission)
+	if err != nil {
+		return domainError(err)
+	}
+	if err := acc.AmendStandingOrder(ctx.Request().Context(), orderId, terms); err != nil {
+		return domainError(err)
+	}
+	return ctx.NoContent(http.StatusAccepted)
+}
+
+type cancelStandingOrderRequest struct {
+	Reason string `json:"reason"`
+}
+
+func (c *AccountController) CancelStandingOrder(ctx echo.Context) error {
+	id, err := c.getAccountId(ctx)
+	if err != nil {
+		return err
+	}
+	orderId, err := getUUIDParam(ctx, "orderId")
+	if err != nil {
+		return err
+	}
+	body := cancelStandingOrderRequest{}
+	if err := ctx.Bind(&body); err != nil {
+		return badRequest(err, err.Error())
+	}
+	acc, err := c.service.AccountFor(ctx.Request().Context(), id, domain.FullPermission)
+	if err != nil {
+		return domainError(err)
+	}
+	if err := acc.CancelStandingOrder(ctx.Request().Context(), orderId, body.Reason); err != nil {
+		return domainError(err)
+	}
+	return ctx.NoContent(http.StatusAccepted)
+}
+
+func (c *AccountController) toStandingOrderTerms(ctx echo.Context, body standingOrderRequest) (domain.StandingOrderTerms, error) {
+	terms := domain.StandingOrderTerms{
+		Amount:   body.Amount,
+		Interval: domain.StandingOrderInterval(body.Interval),
+		Details:  domain.TransactionDetails(body.transactionDetails),
+	}
+	if body.ToAccount != "" {
+		toAccountId, err := c.service.ResolveAccountId(ctx.Request().Context(), body.ToAccount)
+		if err != nil {
+			return terms, domainError(err)
+		}
+		terms.ToAccountId = toAccountId
+	}
+	var err error
+	if terms.StartDate, err = parseOptionalDate(body.StartDate); err != nil {
+		return terms, err
+	}
+	if terms.EndDate, err = parseOptionalDate(body.EndDate); err != nil {
+		return terms, err
+	}
+	return terms, nil
+}
+
+func parseOptionalDate(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	date, err := time.Parse(dateLayout, value)
+	if err != nil {
+		return time.Time{}, badRequest(err, fmt.Sprintf("%s is not a valid date, use %s", value, dateLayout))
+	}
+	return date, nil
+}
+
+func toStandingOrderResponse(order domain.StandingOrder) standingOrderResponse {
+	r := standingOrderResponse{
+		OrderId:            order.OrderId,
+		Amount:             order.Amount,
+		Interval:           string(order.Interval),
+		StartDate:          order.StartDate.Format(dateLayout),
+		NextExecution:      order.NextExecution.Format(dateLayout),
+		Attempts:           order.Attempts,
+		transactionDetails: transactionDetails(order.Details),
+	}
+	if order.IsTransfer() {
+		r.ToAccountId = &order.ToAccountId
+	}
+	if !order.EndDate.IsZero() {
+		endDate := order.EndDate.Format(dateLayout)
+		r.EndDate = &endDate
+	}
+	if !order.RetryAt.IsZero() {
+		r.RetryAt = &order.RetryAt
+	}
+	return r
+}
@@ -1,16 +1,46 @@
 package main
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
+	"github.com/thomaszub/go-es-example/api"
+	"github.com/thomaszub/go-es-example/database"
+	"github.com/thomaszub/go-es-example/domain"
+)
+
+const (
+	cassandraEventStore = "cassandra"
+	fileEventStore      = "file"
 )
 
 type Config struct {
-	CassandraCluster  []string
-	CassandraKeyspace string
+	EventStore              string
+	CassandraCluster        []string
+	CassandraKeyspace       string
+	FileStoreDir            string
+	FileStoreFsync          database.FsyncPolicy
+	FileStoreFsyncInterval  time.Duration
+	ReadTimeout             time.Duration
+	WriteTimeout            time.Duration
+	InstanceId              string
+	HoldExpiryInterval      time.Duration
+	Users                   []api.User
+	Approval                domain.ApprovalPolicy
+	ApprovalExpiryInterval  time.Duration
+	FxRateFile              string
+	InterestAccrualInterval time.Duration
+	Fees                    domain.FeeSchedules
+	Numbering               domain.AccountNumbering
+	StandingOrderInterval   time.Duration
+	StandingOrderRetry      domain.StandingOrderRetry
+	DirectDebit             domain.DirectDebitPolicy
 }
 
 func LoadConfig() (Config, error) {
@@ -19,16 +49,193 @@ func LoadConfig() (Config, error) {
 	if err != nil {
 		return cfg, err
 	}
+	cfg.ReadTimeout, err = getDurationOrDefault("READ_TIMEOUT", "5s")
+	if err != nil {
+		return cfg, err
+	}
+	cfg.WriteTimeout, err = getDurationOrDefault("WRITE_TIMEOUT", "5s")
+	if err != nil {
+		return cfg, err
+	}
+	hostname, err := os.Hostname()
+	if err != nil {
+		return cfg, err
+	}
+	cfg.InstanceId = getEnvOrDefault("INSTANCE_ID", fmt.Sprintf("%s-%d", hostname, os.Getpid()))
+	cfg.HoldExpiryInterval, err = getPositiveDurationOrDefault("HOLD_EXPIRY_INTERVAL", "1m")
+	if err != nil {
+		return cfg, err
+	}
+	cfg.Users, err = api.ParseUsers(os.Getenv("API_USERS"))
+	if err != nil {
+		return cfg, err
+	}
+	if err := loadApprovalConfig(&cfg); err != nil {
+		return cfg, err
+	}
+	cfg.FxRateFile = getEnvOrDefault("FX_RATE_FILE", "")
+	cfg.InterestAccrualInterval, err = getPositiveDurationOrDefault("INTEREST_ACCRUAL_INTERVAL", "1h")
+	if err != nil {
+		return cfg, err
+	}
+	cfg.Fees, err = loadFeeSchedules(getEnvOrDefault("FEE_SCHEDULE_FILE", ""))
+	if err != nil {
+		return cfg, err
+	}
+	cfg.Numbering = domain.AccountNumbering{
+		Country:  getEnvOrDefault("ACCOUNT_NUMBER_COUNTRY", domain.DefaultAccountNumbering.Country),
+		BankCode: getEnvOrDefault("ACCOUNT_NUMBER_BANK_CODE", domain.DefaultAccountNumbering.BankCode),
+	}
+	if err := cfg.Numbering.Validate(); err != nil {
+		return cfg, fmt.Errorf("account numbering is not valid: %w", err)
+	}
+	if err := loadStandingOrderConfig(&cfg); err != nil {
+		return cfg, err
+	}
+	refundDays, err := strconv.Atoi(getEnvOrDefault("DIRECT_DEBIT_REFUND_DAYS", strconv.Itoa(domain.DefaultDirectDebitRefundDays)))
+	if err != nil || refundDays < 1 {
+		return cfg, errors.New("DIRECT_DEBIT_REFUND_DAYS must be a positive number")
+	}
+	cfg.DirectDebit.RefundDays = refundDays
+	cfg.EventStore = getEnvOrDefault("EVENT_STORE", cassandraEventStore)
+	if err := cfg.LoadEventStore(cfg.EventStore); err != nil {
+		return cfg, err
+	}
+	return cfg, nil
+}
+
+func (cfg *Config) LoadEventStore(store string) error {
+	switch store {
+	case cassandraEventStore:
+		return loadCassandraConfig(cfg)
+	case fileEventStore:
+		return loadFileStoreConfig(cfg)
+	default:
+		return fmt.Errorf("event store %s is not supported", store)
+	}
+}
+
+func loadCassandraConfig(cfg *Config) error {
 	cassandraCluster := strings.TrimSpace(os.Getenv("CASSANDRA_CLUSTER"))
 	if cassandraCluster == "" {
-		return cfg, errors.New("CASSANDRA_CLUSTER is not set")
+		return errors.New("CASSANDRA_CLUSTER is not set")
 	}
 	cfg.CassandraCluster = strings.Split(cassandraCluster, ",")
 
 	cassandraKeyspace := strings.TrimSpace(os.Getenv("CASSANDRA_KEYSPACE"))
 	if cassandraKeyspace == "" {
-		return cfg, errors.New("CASSANDRA_KEYSPACE is not set")
+		return errors.New("CASSANDRA_KEYSPACE is not set")
 	}
 	cfg.CassandraKeyspace = cassandraKeyspace
-	return cfg, nil
+	return nil
+}
+
+func loadFileStoreConfig(cfg *Config) error {
+	cfg.FileStoreDir = getEnvOrDefault("FILE_STORE_DIR", "data")
+	fsync, err := database.ParseFsyncPolicy(getEnvOrDefault("FILE_STORE_FSYNC", string(database.FsyncAlways)))
+	if err != nil {
+		return err
+	}
+	cfg.FileStoreFsync = fsync
+	interval, err := getPositiveDurationOrDefault("FILE_STORE_FSYNC_INTERVAL", "1s")
+	if err != nil {
+		return err
+	}
+	cfg.FileStoreFsyncInterval = interval
+	return nil
+}
+
+func loadApprovalConfig(cfg *Config) error {
+	threshold, err := strconv.ParseFloat(getEnvOrDefault("APPROVAL_WITHDRAWAL_THRESHOLD", "0"), 64)
+	if err != nil {
+		return fmt.Errorf("APPROVAL_WITHDRAWAL_THRESHOLD is not a valid amount: %w", err)
+	}
+	cfg.Approval.WithdrawalThreshold = threshold
+	limitLowering, err := strconv.ParseBool(getEnvOrDefault("APPROVAL_LIMIT_LOWERING", "false"))
+	if err != nil {
+		return fmt.Errorf("APPROVAL_LIMIT_LOWERING is not a valid boolean: %w", err)
+	}
+	cfg.Approval.LimitLowering = limitLowering
+	cfg.Approval.Expiry, err = getDurationOrDefault("APPROVAL_EXPIRY", "24h")
+	if err != nil {
+		return err
+	}
+	cfg.ApprovalExpiryInterval, err = getPositiveDurationOrDefault("APPROVAL_EXPIRY_INTERVAL", "1m")
+	return err
+}
+
+func loadStandingOrderConfig(cfg *Config) error {
+	var err error
+	cfg.StandingOrderInterval, err = getPositiveDurationOrDefault("STANDING_ORDER_INTERVAL", "1h")
+	if err != nil {
+		return err
+	}
+	maxAttempts, err := strconv.Atoi(getEnvOrDefault("STANDING_ORDER_MAX_ATTEMPTS", "3"))
+	if err != nil || maxAttempts < 1 {
+		return errors.New("STANDING_ORDER_MAX_ATTEMPTS must be a positive number")
+	}
+	cfg.StandingOrderRetry.MaxAttempts = maxAttempts
+	cfg.StandingOrderRetry.Delay, err = getDurationOrDefault("STANDING_ORDER_RETRY_DELAY", "24h")
+	return err
+}
+
+type feeScheduleFile map[string]struct {
+	Withdrawal     float64 `json:"withdrawal"`
+	OverdraftUsage float64 `json:"overdraftUsage"`
+	Maintenance    float64 `json:"maintenance"`
+	OverdraftRate  float64 `json:"overdraftRate"`
+}
+
+func loadFeeSchedules(path string) (domain.FeeSchedules, error) {
+	fees := domain.FeeSchedules{}
+	if path == "" {
+		return fees, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fees, err
+	}
+	file := feeScheduleFile{}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return fees, fmt.Errorf("FEE_SCHEDULE_FILE %s is not valid: %w", path, err)
+	}
+	for name, entry := range file {
+		accountType, err := domain.ParseAccountType(name)
+		if err != nil {
+			return fees, err
+		}
+		schedule := domain.FeeSchedule(entry)
+		if err := schedule.Validate(); err != nil {
+			return fees, fmt.Errorf("fee schedule of %s: %w", accountType, err)
+		}
+		fees[accountType] = schedule
+	}
+	return fees, nil
+}
+
+func getEnvOrDefault(key, defaultValue string) string {
+	value := strings.TrimSpace(os.Getenv(key))
+	if value == "" {
+		return defaultValue
+	}
+	return value
+}
+
+func getDurationOrDefault(key, defaultValue string) (time.Duration, error) {
+	duration, err := time.ParseDuration(getEnvOrDefault(key, defaultValue))
+	if err != nil {
+		return 0, fmt.Errorf("%s is not a valid duration: %w", key, err)
+	}
+	return duration, nil
+}
+
+func getPositiveDurationOrDefault(key, defaultValue string) (time.Duration, error) {
+	duration, err := getDurationOrDefault(key, defaultValue)
+	if err != nil {
+		return 0, err
+	}
+	if duration <= 0 {
+		return 0, fmt.Errorf("%s must be a positive duration", key)
+	}
+	return duration, nil
 }
@@ -0,0 +1,143 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"sync"
+
+	"github.com/gocql/gocql"
+	"github.com/scylladb/gocqlx/v2"
+	"github.com/scylladb/gocqlx/v2/qb"
+	"github.com/scylladb/gocqlx/v2/table"
+	"github.com/thomaszub/go-es-example/domain"
+)
+
+var accountNumberTable = table.New(table.Metadata{
+	Name:    "account_number",
+	Columns: []string{"account_number", "account_id"},
+	PartKey: []string{"account_number"},
+})
+
+type PersistableAccountNumber struct {
+	AccountNumber string
+	AccountId     gocql.UUID
+}
+
+type CqlAccountNumberRepository struct {
+	session gocqlx.Session
+}
+
+func InitAccountNumberRepository(session *gocql.Session) CqlAccountNumberRepository {
+	return CqlAccountNumberRepository{
+		session: gocqlx.NewSession(session),
+	}
+}
+
+func (r *CqlAccountNumberRepository) Register(ctx context.Context, number domain.AccountNumber, accountId gocql.UUID) (bool, error) {
+	insert := qb.Insert(accountNumberTable.Name()).Columns("account_number", "account_id").Unique()
+	pn := PersistableAccountNumber{AccountNumber: string(number), AccountId: accountId}
+	return r.session.Query(insert.ToCql()).WithContext(ctx).BindStruct(pn).ExecCASRelease()
+}
+
+func (r *CqlAccountNumberRepository) Lookup(ctx context.Context, number domain.AccountNumber) (gocql.UUID, bool, error) {
+	var loaded []PersistableAccountNumber
+	q := r.session.Query(accountNumberTable.Get()).WithContext(ctx).BindMap(qb.M{"account_number": string(number)})
+	if err := q.SelectRelease(&loaded); err != nil {
+		return gocql.UUID{}, false, err
+	}
+	if len(loaded) == 0 {
+		return gocql.UUID{}, false, nil
+	}
+	return loaded[0].AccountId, true, nil
+}
+
+func (r *CqlAccountNumberRepository) Release(ctx context.Context, number domain.AccountNumber, accountId gocql.UUID) error {
+	remove := qb.Delete(accountNumberTable.Name()).Where(qb.Eq("account_number")).If(qb.Eq("account_id"))
+	pn := PersistableAccountNumber{AccountNumber: string(number), AccountId: accountId}
+	_, err := r.session.Query(remove.ToCql()).WithContext(ctx).BindStruct(pn).ExecCASRelease()
+	return err
+}
+
+type FileAccountNumberRepository struct {
+	mu      sync.RWMutex
+	log     *segmentLog
+	numbers map[domain.AccountNumber]gocql.UUID
+}
+
+func OpenFileAccountNumberRepository(dir string, options SegmentLogOptions) (*FileAccountNumberRepository, error) {
+	r := &FileAccountNumberRepository{
+		numbers: map[domain.AccountNumber]gocql.UUID{},
+	}
+	log, err := openSegmentLog(filepath.Join(dir, "numbers"), options, func(data []byte) error {
+		var pn PersistableAccountNumber
+		if err := json.Unmarshal(data, &pn); err != nil {
+			return err
+		}
+		if pn.AccountId == (gocql.UUID{}) {
+			delete(r.numbers, domain.AccountNumber(pn.AccountNumber))
+			return nil
+		}
+		r.numbers[domain.AccountNumber(pn.AccountNumber)] = pn.AccountId
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	r.log = log
+	return r, nil
+}
+
+func (r *FileAccountNumberRepository) Register(ctx context.Context, number domain.AccountNumber, accountId gocql.UUID) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	data, err := json.Marshal(PersistableAccountNumber{AccountNumber: string(number), AccountId: accountId})
+	if err != nil {
+		return false, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.numbers[number]; ok {
+		return false, nil
+	}
+	if err := r.log.Append(data); err != nil {
+		return false, err
+	}
+	r.numbers[number] = accountId
+	return true, nil
+}
+
+func (r *FileAccountNumberRepository) Lookup(ctx context.Context, number domain.AccountNumber) (gocql.UUID, bool, error) {
+	if err := ctx.Err(); err != nil {
+		return gocql.UUID{}, false, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	accountId, ok := r.numbers[number]
+	return accountId, ok, nil
+}
+
+func (r *FileAccountNumberRepository) Release(ctx context.Context, number domain.AccountNumber, accountId gocql.UUID) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	data, err := json.Marshal(PersistableAccountNumber{AccountNumber: string(number)})
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if registered, ok := r.numbers[number]; !ok || registered != accountId {
+		return nil
+	}
+	if err := r.log.Append(data); err != nil {
+		return err
+	}
+	delete(r.numbers, number)
+	return nil
+}
+
+func (r *FileAccountNumberRepository) Close() error {
+	return r.log.Close()
+}
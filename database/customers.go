@@ -0,0 +1,314 @@
+package database
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/gocql/gocql"
+	"github.com/scylladb/gocqlx/v2"
+	"github.com/scylladb/gocqlx/v2/qb"
+	"github.com/scylladb/gocqlx/v2/table"
+	"github.com/thomaszub/go-es-example/domain"
+)
+
+var customerEventTable = table.New(table.Metadata{
+	Name:    "customer_event",
+	Columns: []string{"customer_id", "event_id", "payload"},
+	PartKey: []string{"customer_id"},
+	SortKey: []string{"event_id"},
+})
+
+type customerEventType string
+
+const (
+	customerCreatedEventType          customerEventType = "customerCreated"
+	customerNameChangedEventType      customerEventType = "customerNameChanged"
+	customerAddressChangedEventType   customerEventType = "customerAddressChanged"
+	customerKycStatusChangedEventType customerEventType = "customerKycStatusChanged"
+	accountLinkedEventType            customerEventType = "accountLinked"
+	accountUnlinkedEventType          customerEventType = "accountUnlinked"
+)
+
+type PersistableCustomerEvent struct {
+	CustomerId gocql.UUID
+	EventId    gocql.UUID
+	Payload    []byte
+}
+
+type CqlCustomerEventRepository struct {
+	session gocqlx.Session
+}
+
+func InitCustomerRepository(session *gocql.Session) CqlCustomerEventRepository {
+	return CqlCustomerEventRepository{
+		session: gocqlx.NewSession(session),
+	}
+}
+
+func (r *CqlCustomerEventRepository) Write(ctx context.Context, events ...domain.CustomerEvent) error {
+	stmt, _ := customerEventTable.Insert()
+	batch := r.session.NewBatch(gocql.UnloggedBatch).WithContext(ctx)
+	for _, event := range events {
+		pe, err := serializeCustomerEvent(event)
+		if err != nil {
+			return err
+		}
+		batch.Query(stmt, pe.CustomerId, pe.EventId, pe.Payload)
+	}
+	return r.session.ExecuteBatch(batch)
+}
+
+func (r *CqlCustomerEventRepository) ReadCustomerEvents(ctx context.Context, customerId gocql.UUID) ([]domain.CustomerEvent, error) {
+	var loadedEvents []PersistableCustomerEvent
+	q := r.session.Query(customerEventTable.Select()).WithContext(ctx).BindMap(qb.M{"customer_id": customerId})
+	if err := q.SelectRelease(&loadedEvents); err != nil {
+		return nil, err
+	}
+	return deserializeCustomerEvents(loadedEvents)
+}
+
+func (r *CqlCustomerEventRepository) ReadAllCustomerIds(ctx context.Context) ([]gocql.UUID, error) {
+	var ids []gocql.UUID
+	q := r.session.Query(qb.Select(customerEventTable.Metadata().Name).Columns("customer_id").Distinct("customer_id").ToCql()).WithContext(ctx)
+	if err := q.SelectRelease(&ids); err != nil {
+		return ids, err
+	}
+	return ids, nil
+}
+
+type FileCustomerEventRepository struct {
+	mu      sync.RWMutex
+	log     *segmentLog
+	streams map[gocql.UUID][]PersistableCustomerEvent
+}
+
+func OpenFileCustomerRepository(dir string, options SegmentLogOptions) (*FileCustomerEventRepository, error) {
+	r := &FileCustomerEventRepository{
+		streams: map[gocql.UUID][]PersistableCustomerEvent{},
+	}
+	log, err := openSegmentLog(filepath.Join(dir, "customers"), options, func(data []byte) error {
+		var pes []PersistableCustomerEvent
+		if err := json.Unmarshal(data, &pes); err != nil {
+			return err
+		}
+		for _, pe := range pes {
+			r.index(pe)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	r.log = log
+	return r, nil
+}
+
+func (r *FileCustomerEventRepository) Write(ctx context.Context, events ...domain.CustomerEvent) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	pes := make([]PersistableCustomerEvent, 0, len(events))
+	for _, event := range events {
+		pe, err := serializeCustomerEvent(event)
+		if err != nil {
+			return err
+		}
+		pes = append(pes, pe)
+	}
+	data, err := json.Marshal(pes)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.log.Append(data); err != nil {
+		return err
+	}
+	for _, pe := range pes {
+		r.index(pe)
+	}
+	return nil
+}
+
+func (r *FileCustomerEventRepository) ReadCustomerEvents(ctx context.Context, customerId gocql.UUID) ([]domain.CustomerEvent, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return deserializeCustomerEvents(r.streams[customerId])
+}
+
+func (r *FileCustomerEventRepository) ReadAllCustomerIds(ctx context.Context) ([]gocql.UUID, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	ids := make([]gocql.UUID, 0, len(r.streams))
+	for id := range r.streams {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return bytes.Compare(ids[i].Bytes(), ids[j].Bytes()) < 0
+	})
+	return ids, nil
+}
+
+func (r *FileCustomerEventRepository) Close() error {
+	return r.log.Close()
+}
+
+func (r *FileCustomerEventRepository) index(pe PersistableCustomerEvent) {
+	stream := r.streams[pe.CustomerId]
+	i := sort.Search(len(stream), func(i int) bool {
+		return compareTimeUUID(stream[i].EventId, pe.EventId) >= 0
+	})
+	if i < len(stream) && stream[i].EventId == pe.EventId {
+		stream[i] = pe
+		return
+	}
+	stream = append(stream, PersistableCustomerEvent{})
+	copy(stream[i+1:], stream[i:])
+	stream[i] = pe
+	r.streams[pe.CustomerId] = stream
+}
+
+func serializeCustomerEvent(event domain.CustomerEvent) (PersistableCustomerEvent, error) {
+	var payload string
+	var err error
+	switch e := event.(type) {
+	case domain.CustomerCreatedEvent:
+		payload, err = marshalPayload(customerCreatedEventType, map[string]interface{}{
+			"name":    e.Name,
+			"address": addressFields(e.Address),
+		})
+	case domain.CustomerNameChangedEvent:
+		payload, err = marshalPayload(customerNameChangedEventType, map[string]interface{}{"name": e.Name})
+	case domain.CustomerAddressChangedEvent:
+		payload, err = marshalPayload(customerAddressChangedEventType, map[string]interface{}{"address": addressFields(e.Address)})
+	case domain.CustomerKycStatusChangedEvent:
+		payload, err = marshalPayload(customerKycStatusChangedEventType, map[string]interface{}{
+			"status": e.Status,
+			"reason": e.Reason,
+		})
+	case domain.AccountLinkedEvent:
+		payload, err = marshalPayload(accountLinkedEventType, map[string]interface{}{"accountId": e.AccountId})
+	case domain.AccountUnlinkedEvent:
+		payload, err = marshalPayload(accountUnlinkedEventType, map[string]interface{}{"accountId": e.AccountId})
+	default:
+		return PersistableCustomerEvent{}, fmt.Errorf("%+v is not a valid customer event", event)
+	}
+	if err != nil {
+		return PersistableCustomerEvent{}, err
+	}
+	return PersistableCustomerEvent{
+		CustomerId: event.GetCustomerId(),
+		EventId:    event.GetEventId(),
+		Payload:    []byte(payload),
+	}, nil
+}
+
+func deserializeCustomerEvents(loadedEvents []PersistableCustomerEvent) ([]domain.CustomerEvent, error) {
+	events := make([]domain.CustomerEvent, 0, len(loadedEvents))
+	for _, loaded := range loadedEvents {
+		e, err := deserializeCustomerEvent(loaded)
+		if err != nil {
+			return events, err
+		}
+		events = append(events, e)
+	}
+	return events, nil
+}
+
+func deserializeCustomerEvent(event PersistableCustomerEvent) (domain.CustomerEvent, error) {
+	payload := make(map[string]interface{})
+	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+		return nil, err
+	}
+	eventType, err := getTypedValue[string](payload, "eventType")
+	if err != nil {
+		return nil, fmt.Errorf("event %s: %w", event.EventId, err)
+	}
+	switch customerEventType(eventType) {
+	case customerCreatedEventType:
+		name, err := getTypedValue[string](payload, "name")
+		if err != nil {
+			return nil, err
+		}
+		address, err := getAddressValue(payload)
+		if err != nil {
+			return nil, err
+		}
+		return domain.CustomerCreatedEvent{CustomerId: event.CustomerId, EventId: event.EventId, Name: name, Address: address}, nil
+	case customerNameChangedEventType:
+		name, err := getTypedValue[string](payload, "name")
+		if err != nil {
+			return nil, err
+		}
+		return domain.CustomerNameChangedEvent{CustomerId: event.CustomerId, EventId: event.EventId, Name: name}, nil
+	case customerAddressChangedEventType:
+		address, err := getAddressValue(payload)
+		if err != nil {
+			return nil, err
+		}
+		return domain.CustomerAddressChangedEvent{CustomerId: event.CustomerId, EventId: event.EventId, Address: address}, nil
+	case customerKycStatusChangedEventType:
+		status, err := getTypedValue[string](payload, "status")
+		if err != nil {
+			return nil, err
+		}
+		reason, err := getOptionalValue[string](payload, "reason")
+		if err != nil {
+			return nil, err
+		}
+		return domain.CustomerKycStatusChangedEvent{CustomerId: event.CustomerId, EventId: event.EventId, Status: domain.KycStatus(status), Reason: reason}, nil
+	case accountLinkedEventType:
+		accountId, err := getUUIDValue(payload, "accountId")
+		if err != nil {
+			return nil, err
+		}
+		return domain.AccountLinkedEvent{CustomerId: event.CustomerId, EventId: event.EventId, AccountId: accountId}, nil
+	case accountUnlinkedEventType:
+		accountId, err := getUUIDValue(payload, "accountId")
+		if err != nil {
+			return nil, err
+		}
+		return domain.AccountUnlinkedEvent{CustomerId: event.CustomerId, EventId: event.EventId, AccountId: accountId}, nil
+	default:
+		return nil, fmt.Errorf("%s is not a valid customer event type for event %s", eventType, event.EventId)
+	}
+}
+
+func addressFields(address domain.Address) map[string]interface{} {
+	return map[string]interface{}{
+		"street":     address.Street,
+		"postalCode": address.PostalCode,
+		"city":       address.City,
+		"country":    address.Country,
+	}
+}
+
+func getAddressValue(payload map[string]interface{}) (domain.Address, error) {
+	fields, err := getTypedValue[map[string]interface{}](payload, "address")
+	if err != nil {
+		return domain.Address{}, err
+	}
+	address := domain.Address{}
+	for key, value := range map[string]*string{
+		"street":     &address.Street,
+		"postalCode": &address.PostalCode,
+		"city":       &address.City,
+		"country":    &address.Country,
+	} {
+		if *value, err = getOptionalValue[string](fields, key); err != nil {
+			return address, err
+		}
+	}
+	return address, nil
+}
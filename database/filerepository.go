@@ -0,0 +1,152 @@
+package database
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/gocql/gocql"
+	"github.com/thomaszub/go-es-example/domain"
+)
+
+type FileAccountEventRepository struct {
+	mu      sync.RWMutex
+	log     *segmentLog
+	streams map[gocql.UUID][]PersistableAccountEvent
+}
+
+func OpenFileRepository(dir string, options SegmentLogOptions) (*FileAccountEventRepository, error) {
+	r := &FileAccountEventRepository{
+		streams: map[gocql.UUID][]PersistableAccountEvent{},
+	}
+	log, err := openSegmentLog(dir, options, func(data []byte) error {
+		var pes []PersistableAccountEvent
+		if bytes.HasPrefix(data, []byte("[")) {
+			if err := json.Unmarshal(data, &pes); err != nil {
+				return err
+			}
+		} else {
+			var pe PersistableAccountEvent
+			if err := json.Unmarshal(data, &pe); err != nil {
+				return err
+			}
+			pes = append(pes, pe)
+		}
+		for _, pe := range pes {
+			r.index(pe)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	r.log = log
+	return r, nil
+}
+
+func (r *FileAccountEventRepository) Write(ctx context.Context, events ...domain.AccountEvent) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	pes, err := serializeEvents(events)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(pes)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.log.Append(data); err != nil {
+		return err
+	}
+	for _, pe := range pes {
+		r.index(pe)
+	}
+	return nil
+}
+
+func (r *FileAccountEventRepository) ReadEvents(ctx context.Context, accountId gocql.UUID, pageState []byte, pageSize int) (domain.AccountEventPage, error) {
+	page := domain.AccountEventPage{}
+	if err := ctx.Err(); err != nil {
+		return page, err
+	}
+	if pageSize <= 0 {
+		return page, fmt.Errorf("page size %d must be positive", pageSize)
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	stream := r.streams[accountId]
+	start := 0
+	if len(pageState) > 0 {
+		lastEventId, err := gocql.UUIDFromBytes(pageState)
+		if err != nil {
+			return page, fmt.Errorf("invalid page state: %w", err)
+		}
+		start = sort.Search(len(stream), func(i int) bool {
+			return compareTimeUUID(stream[i].EventId, lastEventId) > 0
+		})
+	}
+	end := start + pageSize
+	if end > len(stream) {
+		end = len(stream)
+	}
+	events, err := deserializeEvents(stream[start:end])
+	if err != nil {
+		return page, err
+	}
+	page.Events = events
+	if end < len(stream) {
+		page.NextPageState = stream[end-1].EventId.Bytes()
+	}
+	return page, nil
+}
+
+func (r *FileAccountEventRepository) ReadAllAccountIds(ctx context.Context) ([]gocql.UUID, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	ids := make([]gocql.UUID, 0, len(r.streams))
+	for id := range r.streams {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return bytes.Compare(ids[i].Bytes(), ids[j].Bytes()) < 0
+	})
+	return ids, nil
+}
+
+func (r *FileAccountEventRepository) Close() error {
+	return r.log.Close()
+}
+
+func (r *FileAccountEventRepository) index(pe PersistableAccountEvent) {
+	stream := r.streams[pe.AccountId]
+	i := sort.Search(len(stream), func(i int) bool {
+		return compareTimeUUID(stream[i].EventId, pe.EventId) >= 0
+	})
+	if i < len(stream) && stream[i].EventId == pe.EventId {
+		stream[i] = pe
+		return
+	}
+	stream = append(stream, PersistableAccountEvent{})
+	copy(stream[i+1:], stream[i:])
+	stream[i] = pe
+	r.streams[pe.AccountId] = stream
+}
+
+func compareTimeUUID(a, b gocql.UUID) int {
+	if a.Timestamp() < b.Timestamp() {
+		return -1
+	}
+	if a.Timestamp() > b.Timestamp() {
+		return 1
+	}
+	return bytes.Compare(a.Bytes(), b.Bytes())
+}
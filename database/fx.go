@@ -0,0 +1,122 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"sync"
+
+	"github.com/gocql/gocql"
+	"github.com/scylladb/gocqlx/v2"
+	"github.com/scylladb/gocqlx/v2/qb"
+	"github.com/scylladb/gocqlx/v2/table"
+	"github.com/thomaszub/go-es-example/fx"
+)
+
+const defaultRateTable = "default"
+
+var rateSnapshotTable = table.New(table.Metadata{
+	Name:    "rate_snapshot",
+	Columns: []string{"rate_table", "snapshot_id", "payload"},
+	PartKey: []string{"rate_table"},
+	SortKey: []string{"snapshot_id"},
+})
+
+type PersistableRateSnapshot struct {
+	RateTable  string
+	SnapshotId gocql.UUID
+	Payload    []byte
+}
+
+type CqlRateSnapshotRepository struct {
+	session gocqlx.Session
+}
+
+func InitRateSnapshotRepository(session *gocql.Session) CqlRateSnapshotRepository {
+	return CqlRateSnapshotRepository{
+		session: gocqlx.NewSession(session),
+	}
+}
+
+func (r *CqlRateSnapshotRepository) Write(ctx context.Context, snapshot fx.RateSnapshot) error {
+	payload, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+	ps := PersistableRateSnapshot{
+		RateTable:  defaultRateTable,
+		SnapshotId: snapshot.SnapshotId,
+		Payload:    payload,
+	}
+	return r.session.Query(rateSnapshotTable.Insert()).WithContext(ctx).BindStruct(ps).ExecRelease()
+}
+
+func (r *CqlRateSnapshotRepository) ReadAll(ctx context.Context) ([]fx.RateSnapshot, error) {
+	var loaded []PersistableRateSnapshot
+	q := r.session.Query(rateSnapshotTable.Select()).WithContext(ctx).BindMap(qb.M{"rate_table": defaultRateTable})
+	if err := q.SelectRelease(&loaded); err != nil {
+		return nil, err
+	}
+	snapshots := make([]fx.RateSnapshot, 0, len(loaded))
+	for _, ps := range loaded {
+		var snapshot fx.RateSnapshot
+		if err := json.Unmarshal(ps.Payload, &snapshot); err != nil {
+			return snapshots, err
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	return snapshots, nil
+}
+
+type FileRateSnapshotRepository struct {
+	mu        sync.RWMutex
+	log       *segmentLog
+	snapshots []fx.RateSnapshot
+}
+
+func OpenFileRateSnapshotRepository(dir string, options SegmentLogOptions) (*FileRateSnapshotRepository, error) {
+	r := &FileRateSnapshotRepository{}
+	log, err := openSegmentLog(filepath.Join(dir, "fx"), options, func(data []byte) error {
+		var snapshot fx.RateSnapshot
+		if err := json.Unmarshal(data, &snapshot); err != nil {
+			return err
+		}
+		r.snapshots = append(r.snapshots, snapshot)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	r.log = log
+	return r, nil
+}
+
+func (r *FileRateSnapshotRepository) Write(ctx context.Context, snapshot fx.RateSnapshot) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.log.Append(data); err != nil {
+		return err
+	}
+	r.snapshots = append(r.snapshots, snapshot)
+	return nil
+}
+
+func (r *FileRateSnapshotRepository) ReadAll(ctx context.Context) ([]fx.RateSnapshot, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]fx.RateSnapshot{}, r.snapshots...), nil
+}
+
+func (r *FileRateSnapshotRepository) Close() error {
+	return r.log.Close()
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	_ "embed"
 	"log"
+	"strings"
 	"text/template"
 
 	"github.com/gocql/gocql"
@@ -26,10 +27,15 @@ func Initialize(cluster []string, keyspace string) error {
 	if err != nil {
 		return err
 	}
-	if err = session.Query(schemaCql).Exec(); err != nil {
-		return err
-	}
 	defer session.Close()
+	for _, statement := range strings.Split(schemaCql, ";") {
+		if strings.TrimSpace(statement) == "" {
+			continue
+		}
+		if err = session.Query(statement).Exec(); err != nil {
+			return err
+		}
+	}
 	log.Println("Schema successfully initialized")
 	return nil
 }
@@ -0,0 +1,39 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/scylladb/gocqlx/v2"
+	"github.com/scylladb/gocqlx/v2/qb"
+	"github.com/scylladb/gocqlx/v2/table"
+)
+
+var jobLeaseTable = table.New(table.Metadata{
+	Name:    "job_lease",
+	Columns: []string{"job_name", "owner"},
+	PartKey: []string{"job_name"},
+})
+
+type CqlLeaseStore struct {
+	session gocqlx.Session
+}
+
+func InitLeaseStore(session *gocql.Session) CqlLeaseStore {
+	return CqlLeaseStore{
+		session: gocqlx.NewSession(session),
+	}
+}
+
+func (s *CqlLeaseStore) Acquire(ctx context.Context, job, owner string, ttl time.Duration) (bool, error) {
+	values := qb.M{"job_name": job, "owner": owner, "_ttl": qb.TTL(ttl)}
+	insert := qb.Insert(jobLeaseTable.Name()).Columns("job_name", "owner").Unique().TTLNamed("_ttl")
+	applied, err := s.session.Query(insert.ToCql()).WithContext(ctx).BindMap(values).ExecCASRelease()
+	if err != nil || applied {
+		return applied, err
+	}
+	renew := qb.Update(jobLeaseTable.Name()).Set("owner").Where(qb.Eq("job_name")).If(qb.EqNamed("owner", "current_owner")).TTLNamed("_ttl")
+	values["current_owner"] = owner
+	return s.session.Query(renew.ToCql()).WithContext(ctx).BindMap(values).ExecCASRelease()
+}
@@ -0,0 +1,122 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"sync"
+
+	"github.com/gocql/gocql"
+	"github.com/scylladb/gocqlx/v2"
+	"github.com/scylladb/gocqlx/v2/qb"
+	"github.com/scylladb/gocqlx/v2/table"
+	"github.com/thomaszub/go-es-example/rules"
+)
+
+const transactionRuleset = "transaction"
+
+var ruleEventTable = table.New(table.Metadata{
+	Name:    "rule_event",
+	Columns: []string{"ruleset", "event_id", "payload"},
+	PartKey: []string{"ruleset"},
+	SortKey: []string{"event_id"},
+})
+
+type PersistableRuleEvent struct {
+	Ruleset string
+	EventId gocql.UUID
+	Payload []byte
+}
+
+type CqlRuleEventRepository struct {
+	session gocqlx.Session
+}
+
+func InitRuleRepository(session *gocql.Session) CqlRuleEventRepository {
+	return CqlRuleEventRepository{
+		session: gocqlx.NewSession(session),
+	}
+}
+
+func (r *CqlRuleEventRepository) Write(ctx context.Context, event rules.RuleEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	pe := PersistableRuleEvent{
+		Ruleset: transactionRuleset,
+		EventId: event.EventId,
+		Payload: payload,
+	}
+	return r.session.Query(ruleEventTable.Insert()).WithContext(ctx).BindStruct(pe).ExecRelease()
+}
+
+func (r *CqlRuleEventRepository) ReadAll(ctx context.Context) ([]rules.RuleEvent, error) {
+	var loadedEvents []PersistableRuleEvent
+	q := r.session.Query(ruleEventTable.Select()).WithContext(ctx).BindMap(qb.M{"ruleset": transactionRuleset})
+	if err := q.SelectRelease(&loadedEvents); err != nil {
+		return nil, err
+	}
+	events := make([]rules.RuleEvent, 0, len(loadedEvents))
+	for _, loaded := range loadedEvents {
+		var event rules.RuleEvent
+		if err := json.Unmarshal(loaded.Payload, &event); err != nil {
+			return events, err
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+type FileRuleEventRepository struct {
+	mu     sync.RWMutex
+	log    *segmentLog
+	events []rules.RuleEvent
+}
+
+func OpenFileRuleRepository(dir string, options SegmentLogOptions) (*FileRuleEventRepository, error) {
+	r := &FileRuleEventRepository{}
+	log, err := openSegmentLog(filepath.Join(dir, "rules"), options, func(data []byte) error {
+		var event rules.RuleEvent
+		if err := json.Unmarshal(data, &event); err != nil {
+			return err
+		}
+		r.events = append(r.events, event)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	r.log = log
+	return r, nil
+}
+
+func (r *FileRuleEventRepository) Write(ctx context.Context, event rules.RuleEvent) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.log.Append(data); err != nil {
+		return err
+	}
+	r.events = append(r.events, event)
+	return nil
+}
+
+func (r *FileRuleEventRepository) ReadAll(ctx context.Context) ([]rules.RuleEvent, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]rules.RuleEvent{}, r.events...), nil
+}
+
+func (r *FileRuleEventRepository) Close() error {
+	return r.log.Close()
+}
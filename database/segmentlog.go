@@ -0,0 +1,270 @@
+package database
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+type FsyncPolicy string
+
+const (
+	FsyncAlways   FsyncPolicy = "always"
+	FsyncInterval FsyncPolicy = "interval"
+	FsyncNever    FsyncPolicy = "never"
+)
+
+func ParseFsyncPolicy(policy string) (FsyncPolicy, error) {
+	switch p := FsyncPolicy(policy); p {
+	case FsyncAlways, FsyncInterval, FsyncNever:
+		return p, nil
+	default:
+		return "", fmt.Errorf("%s is not a valid fsync policy", policy)
+	}
+}
+
+type SegmentLogOptions struct {
+	FsyncPolicy    FsyncPolicy
+	FsyncInterval  time.Duration
+	MaxSegmentSize int64
+}
+
+const (
+	recordHeaderSize      = 8
+	segmentFilePattern    = "segment-%08d.log"
+	defaultMaxSegmentSize = 64 * 1024 * 1024
+)
+
+var errTornRecord = errors.New("torn record")
+
+type segmentLog struct {
+	mu          sync.Mutex
+	dir         string
+	options     SegmentLogOptions
+	active      *os.File
+	activeIndex int
+	activeSize  int64
+	dirty       bool
+	failed      error
+	stop        chan struct{}
+	stopped     sync.WaitGroup
+}
+
+func openSegmentLog(dir string, options SegmentLogOptions, replay func(data []byte) error) (*segmentLog, error) {
+	if options.MaxSegmentSize <= 0 {
+		options.MaxSegmentSize = defaultMaxSegmentSize
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	indices, err := listSegments(dir)
+	if err != nil {
+		return nil, err
+	}
+	l := &segmentLog{
+		dir:     dir,
+		options: options,
+		stop:    make(chan struct{}),
+	}
+	for i, index := range indices {
+		last := i == len(indices)-1
+		size, err := replaySegment(l.segmentPath(index), last, replay)
+		if err != nil {
+			return nil, err
+		}
+		l.activeIndex = index
+		l.activeSize = size
+	}
+	if len(indices) == 0 {
+		l.activeIndex = 1
+	}
+	l.active, err = os.OpenFile(l.segmentPath(l.activeIndex), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	if options.FsyncPolicy == FsyncInterval {
+		l.stopped.Add(1)
+		go l.syncPeriodically()
+	}
+	return l, nil
+}
+
+func (l *segmentLog) Append(data []byte) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.failed != nil {
+		return l.failed
+	}
+	record := encodeRecord(data)
+	if l.activeSize > 0 && l.activeSize+int64(len(record)) > l.options.MaxSegmentSize {
+		if err := l.roll(); err != nil {
+			return err
+		}
+	}
+	if _, err := l.active.Write(record); err != nil {
+		return l.rollback(err)
+	}
+	l.activeSize += int64(len(record))
+	l.dirty = true
+	if l.options.FsyncPolicy == FsyncAlways {
+		return l.sync()
+	}
+	return nil
+}
+
+func (l *segmentLog) rollback(cause error) error {
+	if err := l.active.Truncate(l.activeSize); err != nil {
+		l.failed = fmt.Errorf("segment %d is unusable after a failed write: %w", l.activeIndex, err)
+		return errors.Join(cause, l.failed)
+	}
+	return cause
+}
+
+func (l *segmentLog) Close() error {
+	close(l.stop)
+	l.stopped.Wait()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := l.sync(); err != nil {
+		return err
+	}
+	return l.active.Close()
+}
+
+func (l *segmentLog) roll() error {
+	if err := l.active.Sync(); err != nil {
+		return err
+	}
+	if err := l.active.Close(); err != nil {
+		return err
+	}
+	next, err := os.OpenFile(l.segmentPath(l.activeIndex+1), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	l.active = next
+	l.activeIndex++
+	l.activeSize = 0
+	l.dirty = false
+	return nil
+}
+
+func (l *segmentLog) sync() error {
+	if !l.dirty {
+		return nil
+	}
+	if err := l.active.Sync(); err != nil {
+		return err
+	}
+	l.dirty = false
+	return nil
+}
+
+func (l *segmentLog) syncPeriodically() {
+	defer l.stopped.Done()
+	ticker := time.NewTicker(l.options.FsyncInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-ticker.C:
+			l.mu.Lock()
+			if err := l.sync(); err != nil {
+				log.Printf("Syncing segment %d failed: %v", l.activeIndex, err)
+			}
+			l.mu.Unlock()
+		}
+	}
+}
+
+func (l *segmentLog) segmentPath(index int) string {
+	return filepath.Join(l.dir, fmt.Sprintf(segmentFilePattern, index))
+}
+
+func listSegments(dir string) ([]int, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var indices []int
+	for _, entry := range entries {
+		var index int
+		if _, err := fmt.Sscanf(entry.Name(), segmentFilePattern, &index); err == nil {
+			indices = append(indices, index)
+		}
+	}
+	sort.Ints(indices)
+	return indices, nil
+}
+
+func replaySegment(path string, last bool, replay func(data []byte) error) (int64, error) {
+	file, err := os.OpenFile(path, os.O_RDWR, 0o644)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return 0, err
+	}
+	maxSize := info.Size()
+	var offset int64
+	for {
+		data, err := readRecord(file, maxSize)
+		if err == io.EOF {
+			return offset, nil
+		}
+		if errors.Is(err, errTornRecord) && last {
+			if err := file.Truncate(offset); err != nil {
+				return 0, err
+			}
+			return offset, file.Sync()
+		}
+		if err != nil {
+			return 0, fmt.Errorf("segment %s is corrupt at offset %d: %w", path, offset, err)
+		}
+		if err := replay(data); err != nil {
+			return 0, err
+		}
+		offset += int64(recordHeaderSize + len(data))
+	}
+}
+
+func encodeRecord(data []byte) []byte {
+	record := make([]byte, recordHeaderSize+len(data))
+	binary.BigEndian.PutUint32(record[0:4], uint32(len(data)))
+	binary.BigEndian.PutUint32(record[4:8], crc32.ChecksumIEEE(data))
+	copy(record[recordHeaderSize:], data)
+	return record
+}
+
+func readRecord(r io.Reader, maxSize int64) ([]byte, error) {
+	header := make([]byte, recordHeaderSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		if err == io.EOF {
+			return nil, err
+		}
+		return nil, errTornRecord
+	}
+	size := int64(binary.BigEndian.Uint32(header[0:4]))
+	if size > maxSize {
+		return nil, errTornRecord
+	}
+	data := make([]byte, size)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, errTornRecord
+	}
+	if crc32.ChecksumIEEE(data) != binary.BigEndian.Uint32(header[4:8]) {
+		return nil, errTornRecord
+	}
+	return data, nil
+}
@@ -0,0 +1,160 @@
+package database
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func openTestLog(t *testing.T, dir string, options SegmentLogOptions) (*segmentLog, []string) {
+	t.Helper()
+	var replayed []string
+	l, err := openSegmentLog(dir, options, func(data []byte) error {
+		replayed = append(replayed, string(data))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("opening segment log failed: %v", err)
+	}
+	return l, replayed
+}
+
+func appendAll(t *testing.T, l *segmentLog, records ...string) {
+	t.Helper()
+	for _, record := range records {
+		if err := l.Append([]byte(record)); err != nil {
+			t.Fatalf("appending %s failed: %v", record, err)
+		}
+	}
+}
+
+func TestSegmentLogReplaysAcrossSegments(t *testing.T) {
+	dir := t.TempDir()
+	options := SegmentLogOptions{FsyncPolicy: FsyncAlways, MaxSegmentSize: 20}
+	l, _ := openTestLog(t, dir, options)
+	appendAll(t, l, "first", "second", "third")
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+	segments, err := listSegments(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(segments) != 3 {
+		t.Fatalf("expected 3 segments, got %d", len(segments))
+	}
+	l, replayed := openTestLog(t, dir, options)
+	defer l.Close()
+	if got := strings.Join(replayed, ","); got != "first,second,third" {
+		t.Fatalf("replayed %s", got)
+	}
+}
+
+func TestSegmentLogRecoversTornTail(t *testing.T) {
+	tests := []struct {
+		name string
+		tail func(record []byte) []byte
+	}{
+		{"partial header", func(record []byte) []byte { return record[:5] }},
+		{"partial payload", func(record []byte) []byte { return record[:len(record)-2] }},
+		{"checksum mismatch", func(record []byte) []byte {
+			torn := append([]byte{}, record...)
+			torn[len(torn)-1] ^= 0xff
+			return torn
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir := t.TempDir()
+			options := SegmentLogOptions{FsyncPolicy: FsyncAlways}
+			l, _ := openTestLog(t, dir, options)
+			appendAll(t, l, "first", "second")
+			if err := l.Close(); err != nil {
+				t.Fatal(err)
+			}
+			path := l.segmentPath(1)
+			intact, err := os.Stat(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			writeTail(t, path, test.tail(encodeRecord([]byte("lost"))))
+
+			l, replayed := openTestLog(t, dir, options)
+			if got := strings.Join(replayed, ","); got != "first,second" {
+				t.Fatalf("replayed %s", got)
+			}
+			recovered, err := os.Stat(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if recovered.Size() != intact.Size() {
+				t.Fatalf("expected the torn tail to be truncated to %d bytes, got %d", intact.Size(), recovered.Size())
+			}
+			appendAll(t, l, "third")
+			if err := l.Close(); err != nil {
+				t.Fatal(err)
+			}
+			l, replayed = openTestLog(t, dir, options)
+			defer l.Close()
+			if got := strings.Join(replayed, ","); got != "first,second,third" {
+				t.Fatalf("replayed %s after appending to the recovered log", got)
+			}
+		})
+	}
+}
+
+func TestSegmentLogRejectsCorruptSealedSegment(t *testing.T) {
+	dir := t.TempDir()
+	options := SegmentLogOptions{FsyncPolicy: FsyncAlways, MaxSegmentSize: 20}
+	l, _ := openTestLog(t, dir, options)
+	appendAll(t, l, "first", "second")
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+	path := l.segmentPath(1)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data[len(data)-1] ^= 0xff
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	_, err = openSegmentLog(dir, options, func([]byte) error { return nil })
+	if err == nil || !strings.Contains(err.Error(), "is corrupt") {
+		t.Fatalf("expected a corrupt segment error, got %v", err)
+	}
+}
+
+func TestSegmentLogBecomesUnusableWhenRollbackFails(t *testing.T) {
+	dir := t.TempDir()
+	l, _ := openTestLog(t, dir, SegmentLogOptions{FsyncPolicy: FsyncNever})
+	appendAll(t, l, "first")
+	if err := l.active.Close(); err != nil {
+		t.Fatal(err)
+	}
+	readOnly, err := os.Open(l.segmentPath(l.activeIndex))
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.active = readOnly
+	if err := l.Append([]byte("second")); err == nil {
+		t.Fatal("expected the write to a read-only segment to fail")
+	}
+	err = l.Append([]byte("third"))
+	if err == nil || !strings.Contains(err.Error(), "unusable") {
+		t.Fatalf("expected the log to be unusable, got %v", err)
+	}
+}
+
+func writeTail(t *testing.T, path string, tail []byte) {
+	t.Helper()
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	if _, err := file.Write(tail); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,1394 @@
+package database
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/thomaszub/go-es-example/domain"
+)
+
+type accountEventType string
+
+const dateLayout = "2006-01-02"
+
+const (
+	accountCreatedEventType          accountEventType = "created"
+	accountDeletedEventType          accountEventType = "deleted"
+	moneyDipositedEventType          accountEventType = "moneyDeposited"
+	moneyWithdrawnEventType          accountEventType = "moneyWithdrawn"
+	limitSetEventType                accountEventType = "limitSet"
+	accountActivatedEventType        accountEventType = "activated"
+	accountFrozenEventType           accountEventType = "frozen"
+	accountUnfrozenEventType         accountEventType = "unfrozen"
+	accountClosedEventType           accountEventType = "closed"
+	accountReopenedEventType         accountEventType = "reopened"
+	holdPlacedEventType              accountEventType = "holdPlaced"
+	holdCapturedEventType            accountEventType = "holdCaptured"
+	holdReleasedEventType            accountEventType = "holdReleased"
+	holdExpiredEventType             accountEventType = "holdExpired"
+	limitPoliciesSetEventType        accountEventType = "limitPoliciesSet"
+	ruleDecisionRecordedEventType    accountEventType = "ruleDecisionRecorded"
+	approvalRequestedEventType       accountEventType = "approvalRequested"
+	approvalGrantedEventType         accountEventType = "approvalGranted"
+	approvalRejectedEventType        accountEventType = "approvalRejected"
+	approvalExpiredEventType         accountEventType = "approvalExpired"
+	transactionReversedEventType     accountEventType = "transactionReversed"
+	exchangeDebitedEventType         accountEventType = "exchangeDebited"
+	exchangeCreditedEventType        accountEventType = "exchangeCredited"
+	interestRateSetEventType         accountEventType = "interestRateSet"
+	interestAccruedEventType         accountEventType = "interestAccrued"
+	interestCreditedEventType        accountEventType = "interestCredited"
+	feeChargedEventType              accountEventType = "feeCharged"
+	holderAddedEventType             accountEventType = "holderAdded"
+	holderPermissionChangedEventType accountEventType = "holderPermissionChanged"
+	holderRemovedEventType           accountEventType = "holderRemoved"
+	standingOrderCreatedEventType    accountEventType = "standingOrderCreated"
+	standingOrderAmendedEventType    accountEventType = "standingOrderAmended"
+	standingOrderCancelledEventType  accountEventType = "standingOrderCancelled"
+	standingOrderExecutedEventType   accountEventType = "standingOrderExecuted"
+	standingOrderFailedEventType     accountEventType = "standingOrderFailed"
+	mandateCreatedEventType          accountEventType = "mandateCreated"
+	mandateRevokedEventType          accountEventType = "mandateRevoked"
+	directDebitCollectedEventType    accountEventType = "directDebitCollected"
+	directDebitRefundedEventType     accountEventType = "directDebitRefunded"
+)
+
+type PersistableAccountEvent struct {
+	AccountId gocql.UUID
+	EventId   gocql.UUID
+	Payload   []byte
+}
+
+func serializeEvent(event domain.AccountEvent) (PersistableAccountEvent, error) {
+	var payload string
+	var err error
+	switch e := event.(type) {
+	case domain.AccountCreatedEvent:
+		fields := map[string]interface{}{
+			"pending":     e.Pending,
+			"currency":    e.Currency,
+			"accountType": e.Type,
+		}
+		if e.CustomerId != (gocql.UUID{}) {
+			fields["customerId"] = e.CustomerId
+		}
+		if e.AccountNumber != "" {
+			fields["accountNumber"] = e.AccountNumber
+		}
+		payload, err = marshalPayload(accountCreatedEventType, fields)
+	case domain.AccountDeletedEvent:
+		payload = fmt.Sprintf(`{"eventType":"%s"}`, accountDeletedEventType)
+	case domain.MoneyDipositedEvent:
+		payload, err = marshalPayload(moneyDipositedEventType, withDetails(map[string]interface{}{"amount": e.Amount, "currency": e.Currency}, e.Details))
+	case domain.MoneyWithdrawnEvent:
+		payload, err = marshalPayload(moneyWithdrawnEventType, withDetails(map[string]interface{}{"amount": e.Amount, "currency": e.Currency}, e.Details))
+	case domain.LimitSetEvent:
+		payload = fmt.Sprintf(`{"eventType":"%s","limit":%f}`, limitSetEventType, e.Limit)
+	case domain.AccountClosedEvent:
+		payload, err = marshalPayload(accountClosedEventType, map[string]interface{}{
+			"finalBalance":      e.FinalBalance,
+			"payoutDestination": e.PayoutDestination,
+		})
+	case domain.AccountReopenedEvent:
+		payload = fmt.Sprintf(`{"eventType":"%s"}`, accountReopenedEventType)
+	case domain.AccountActivatedEvent:
+		payload = fmt.Sprintf(`{"eventType":"%s"}`, accountActivatedEventType)
+	case domain.AccountFrozenEvent:
+		payload, err = marshalPayload(accountFrozenEventType, map[string]interface{}{"reason": e.Reason})
+	case domain.AccountUnfrozenEvent:
+		payload = fmt.Sprintf(`{"eventType":"%s"}`, accountUnfrozenEventType)
+	case domain.HoldPlacedEvent:
+		payload, err = marshalPayload(holdPlacedEventType, map[string]interface{}{
+			"holdId":    e.HoldId,
+			"amount":    e.Amount,
+			"expiresAt": e.ExpiresAt.Format(time.RFC3339Nano),
+		})
+	case domain.HoldCapturedEvent:
+		payload, err = marshalPayload(holdCapturedEventType, map[string]interface{}{
+			"holdId": e.HoldId,
+			"amount": e.Amount,
+		})
+	case domain.HoldReleasedEvent:
+		payload, err = marshalPayload(holdReleasedEventType, map[string]interface{}{"holdId": e.HoldId})
+	case domain.HoldExpiredEvent:
+		payload, err = marshalPayload(holdExpiredEventType, map[string]interface{}{"holdId": e.HoldId})
+	case domain.LimitPoliciesSetEvent:
+		payload, err = marshalPayload(limitPoliciesSetEventType, map[string]interface{}{
+			"maxDailyWithdrawal":   e.Policies.MaxDailyWithdrawal,
+			"maxHourlyWithdrawals": e.Policies.MaxHourlyWithdrawals,
+			"maxTransactionAmount": e.Policies.MaxTransactionAmount,
+		})
+	case domain.RuleDecisionRecordedEvent:
+		payload, err = marshalPayload(ruleDecisionRecordedEventType, map[string]interface{}{
+			"command":     e.Command,
+			"amount":      e.Amount,
+			"outcome":     e.Outcome,
+			"rule":        e.Rule,
+			"ruleVersion": e.RuleVersion,
+		})
+	case domain.ApprovalRequestedEvent:
+		payload, err = marshalPayload(approvalRequestedEventType, withDetails(map[string]interface{}{
+			"requestId":   e.RequestId,
+			"command":     e.Command,
+			"amount":      e.Amount,
+			"limit":       e.Limit,
+			"requestedBy": e.RequestedBy,
+			"expiresAt":   e.ExpiresAt.Format(time.RFC3339Nano),
+		}, e.Details))
+	case domain.ApprovalGrantedEvent:
+		payload, err = marshalPayload(approvalGrantedEventType, map[string]interface{}{
+			"requestId":  e.RequestId,
+			"approvedBy": e.ApprovedBy,
+		})
+	case domain.ApprovalRejectedEvent:
+		payload, err = marshalPayload(approvalRejectedEventType, map[string]interface{}{
+			"requestId":  e.RequestId,
+			"rejectedBy": e.RejectedBy,
+			"reason":     e.Reason,
+		})
+	case domain.ApprovalExpiredEvent:
+		payload, err = marshalPayload(approvalExpiredEventType, map[string]interface{}{"requestId": e.RequestId})
+	case domain.TransactionReversedEvent:
+		payload, err = marshalPayload(transactionReversedEventType, map[string]interface{}{
+			"reversedEventId": e.ReversedEventId,
+			"command":         e.Command,
+			"amount":          e.Amount,
+			"reason":          e.Reason,
+		})
+	case domain.ExchangeDebitedEvent:
+		payload, err = marshalPayload(exchangeDebitedEventType, exchangeFields(exchangeEvent(e)))
+	case domain.ExchangeCreditedEvent:
+		payload, err = marshalPayload(exchangeCreditedEventType, exchangeFields(exchangeEvent(e)))
+	case domain.InterestRateSetEvent:
+		payload, err = marshalPayload(interestRateSetEventType, map[string]interface{}{
+			"rate":     e.Rate,
+			"dayCount": e.DayCount,
+		})
+	case domain.InterestAccruedEvent:
+		payload, err = marshalPayload(interestAccruedEventType, map[string]interface{}{
+			"from":        e.From.Format(dateLayout),
+			"through":     e.Through.Format(dateLayout),
+			"amount":      e.Amount,
+			"rate":        e.Rate,
+			"dayCount":    e.DayCount,
+			"debitAmount": e.DebitAmount,
+			"debitRate":   e.DebitRate,
+		})
+	case domain.InterestCreditedEvent:
+		payload, err = marshalPayload(interestCreditedEventType, map[string]interface{}{
+			"amount": e.Amount,
+			"month":  e.Month,
+		})
+	case domain.HolderAddedEvent:
+		payload, err = marshalPayload(holderAddedEventType, holderFields(e.CustomerId, e.Permission, e.WithdrawalLimit))
+	case domain.HolderPermissionChangedEvent:
+		payload, err = marshalPayload(holderPermissionChangedEventType, holderFields(e.CustomerId, e.Permission, e.WithdrawalLimit))
+	case domain.HolderRemovedEvent:
+		payload, err = marshalPayload(holderRemovedEventType, map[string]interface{}{"customerId": e.CustomerId})
+	case domain.FeeChargedEvent:
+		payload, err = marshalPayload(feeChargedEventType, map[string]interface{}{
+			"kind":     e.Kind,
+			"amount":   e.Amount,
+			"currency": e.Currency,
+			"month":    e.Month,
+		})
+	case domain.StandingOrderCreatedEvent:
+		fields := withDetails(map[string]interface{}{
+			"orderId":   e.OrderId,
+			"amount":    e.Amount,
+			"interval":  e.Interval,
+			"startDate": e.StartDate.Format(dateLayout),
+		}, e.Details)
+		if e.ToAccountId != (gocql.UUID{}) {
+			fields["toAccountId"] = e.ToAccountId
+		}
+		payload, err = marshalPayload(standingOrderCreatedEventType, withOptionalDate(fields, "endDate", e.EndDate))
+	case domain.StandingOrderAmendedEvent:
+		fields := withDetails(map[string]interface{}{
+			"orderId":       e.OrderId,
+			"amount":        e.Amount,
+			"interval":      e.Interval,
+			"nextExecution": e.NextExecution.Format(dateLayout),
+		}, e.Details)
+		payload, err = marshalPayload(standingOrderAmendedEventType, withOptionalDate(fields, "endDate", e.EndDate))
+	case domain.StandingOrderCancelledEvent:
+		payload, err = marshalPayload(standingOrderCancelledEventType, map[string]interface{}{
+			"orderId": e.OrderId,
+			"reason":  e.Reason,
+		})
+	case domain.StandingOrderExecutedEvent:
+		payload, err = marshalPayload(standingOrderExecutedEventType, withOptionalDate(map[string]interface{}{
+			"orderId":       e.OrderId,
+			"dueDate":       e.DueDate.Format(dateLayout),
+			"amount":        e.Amount,
+			"transactionId": e.TransactionId,
+		}, "nextExecution", e.NextExecution))
+	case domain.StandingOrderFailedEvent:
+		fields := withOptionalDate(map[string]interface{}{
+			"orderId": e.OrderId,
+			"dueDate": e.DueDate.Format(dateLayout),
+			"attempt": e.Attempt,
+			"reason":  e.Reason,
+		}, "nextExecution", e.NextExecution)
+		if !e.RetryAt.IsZero() {
+			fields["retryAt"] = e.RetryAt
+		}
+		payload, err = marshalPayload(standingOrderFailedEventType, fields)
+	case domain.MandateCreatedEvent:
+		payload, err = marshalPayload(mandateCreatedEventType, map[string]interface{}{
+			"mandateId":        e.MandateId,
+			"creditorId":       e.CreditorId,
+			"creditorName":     e.CreditorName,
+			"reference":        e.Reference,
+			"maxAmount":        e.MaxAmount,
+			"maxMonthlyAmount": e.MaxMonthlyAmount,
+		})
+	case domain.MandateRevokedEvent:
+		payload, err = marshalPayload(mandateRevokedEventType, map[string]interface{}{
+			"mandateId": e.MandateId,
+			"reason":    e.Reason,
+		})
+	case domain.DirectDebitCollectedEvent:
+		payload, err = marshalPayload(directDebitCollectedEventType, withDetails(map[string]interface{}{
+			"mandateId": e.MandateId,
+			"amount":    e.Amount,
+			"currency":  e.Currency,
+		}, e.Details))
+	case domain.DirectDebitRefundedEvent:
+		payload, err = marshalPayload(directDebitRefundedEventType, map[string]interface{}{
+			"collectionId": e.CollectionId,
+			"mandateId":    e.MandateId,
+			"amount":       e.Amount,
+			"reason":       e.Reason,
+		})
+	default:
+		return PersistableAccountEvent{}, fmt.Errorf("%+v is not a valid account event", event)
+	}
+	if err != nil {
+		return PersistableAccountEvent{}, err
+	}
+	return PersistableAccountEvent{
+		AccountId: event.GetAccountId(),
+		EventId:   event.GetEventId(),
+		Payload:   []byte(payload),
+	}, nil
+}
+
+func serializeEvents(events []domain.AccountEvent) ([]PersistableAccountEvent, error) {
+	if len(events) == 0 {
+		return nil, errors.New("no events to write")
+	}
+	pes := make([]PersistableAccountEvent, 0, len(events))
+	for _, event := range events {
+		if event.GetAccountId() != events[0].GetAccountId() {
+			return nil, fmt.Errorf("events of accounts %s and %s can not be written together", events[0].GetAccountId(), event.GetAccountId())
+		}
+		pe, err := serializeEvent(event)
+		if err != nil {
+			return nil, err
+		}
+		pes = append(pes, pe)
+	}
+	return pes, nil
+}
+
+func deserializeEvent(event PersistableAccountEvent) (domain.AccountEvent, error) {
+	payload := make(map[string]interface{})
+	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+		return nil, err
+	}
+	typeI, ok := payload["eventType"]
+	if !ok {
+		return nil, fmt.Errorf("type is not set on event %s", event.EventId.String())
+	}
+	eventTypeF, ok := typeI.(string)
+	if !ok {
+		return nil, fmt.Errorf("%v is not a valid event type for event %s", typeI, event.EventId.String())
+	}
+	eventType := accountEventType(eventTypeF)
+	var e domain.AccountEvent
+	var err error
+	switch eventType {
+	case accountCreatedEventType:
+		e, err = deserializeAccountCreatedEvent(event.AccountId, event.EventId, payload)
+	case accountDeletedEventType:
+		e = domain.AccountDeletedEvent{
+			AccountId: event.AccountId,
+			EventId:   event.EventId,
+		}
+	case moneyDipositedEventType:
+		e, err = deserializeMoneyDipositedEvent(event.AccountId, event.EventId, payload)
+	case moneyWithdrawnEventType:
+		e, err = deserializeMoneyWithdrawnEvent(event.AccountId, event.EventId, payload)
+	case limitSetEventType:
+		e, err = deserializeLimitSetEvent(event.AccountId, event.EventId, payload)
+	case accountClosedEventType:
+		e, err = deserializeAccountClosedEvent(event.AccountId, event.EventId, payload)
+	case accountReopenedEventType:
+		e = domain.AccountReopenedEvent{
+			AccountId: event.AccountId,
+			EventId:   event.EventId,
+		}
+	case accountActivatedEventType:
+		e = domain.AccountActivatedEvent{
+			AccountId: event.AccountId,
+			EventId:   event.EventId,
+		}
+	case accountFrozenEventType:
+		e, err = deserializeAccountFrozenEvent(event.AccountId, event.EventId, payload)
+	case accountUnfrozenEventType:
+		e = domain.AccountUnfrozenEvent{
+			AccountId: event.AccountId,
+			EventId:   event.EventId,
+		}
+	case holdPlacedEventType:
+		e, err = deserializeHoldPlacedEvent(event.AccountId, event.EventId, payload)
+	case holdCapturedEventType:
+		e, err = deserializeHoldCapturedEvent(event.AccountId, event.EventId, payload)
+	case holdReleasedEventType:
+		e, err = deserializeHoldReleasedEvent(event.AccountId, event.EventId, payload)
+	case holdExpiredEventType:
+		e, err = deserializeHoldExpiredEvent(event.AccountId, event.EventId, payload)
+	case limitPoliciesSetEventType:
+		e, err = deserializeLimitPoliciesSetEvent(event.AccountId, event.EventId, payload)
+	case ruleDecisionRecordedEventType:
+		e, err = deserializeRuleDecisionRecordedEvent(event.AccountId, event.EventId, payload)
+	case approvalRequestedEventType:
+		e, err = deserializeApprovalRequestedEvent(event.AccountId, event.EventId, payload)
+	case approvalGrantedEventType:
+		e, err = deserializeApprovalGrantedEvent(event.AccountId, event.EventId, payload)
+	case approvalRejectedEventType:
+		e, err = deserializeApprovalRejectedEvent(event.AccountId, event.EventId, payload)
+	case approvalExpiredEventType:
+		e, err = deserializeApprovalExpiredEvent(event.AccountId, event.EventId, payload)
+	case transactionReversedEventType:
+		e, err = deserializeTransactionReversedEvent(event.AccountId, event.EventId, payload)
+	case exchangeDebitedEventType:
+		var exchange exchangeEvent
+		exchange, err = deserializeExchangeEvent(event.AccountId, event.EventId, payload)
+		e = domain.ExchangeDebitedEvent(exchange)
+	case exchangeCreditedEventType:
+		var exchange exchangeEvent
+		exchange, err = deserializeExchangeEvent(event.AccountId, event.EventId, payload)
+		e = domain.ExchangeCreditedEvent(exchange)
+	case interestRateSetEventType:
+		e, err = deserializeInterestRateSetEvent(event.AccountId, event.EventId, payload)
+	case interestAccruedEventType:
+		e, err = deserializeInterestAccruedEvent(event.AccountId, event.EventId, payload)
+	case interestCreditedEventType:
+		e, err = deserializeInterestCreditedEvent(event.AccountId, event.EventId, payload)
+	case feeChargedEventType:
+		e, err = deserializeFeeChargedEvent(event.AccountId, event.EventId, payload)
+	case holderAddedEventType:
+		var holder domain.Holder
+		holder, err = getHolderValue(payload)
+		e = domain.HolderAddedEvent{AccountId: event.AccountId, EventId: event.EventId, CustomerId: holder.CustomerId, Permission: holder.Permission, WithdrawalLimit: holder.WithdrawalLimit}
+	case holderPermissionChangedEventType:
+		var holder domain.Holder
+		holder, err = getHolderValue(payload)
+		e = domain.HolderPermissionChangedEvent{AccountId: event.AccountId, EventId: event.EventId, CustomerId: holder.CustomerId, Permission: holder.Permission, WithdrawalLimit: holder.WithdrawalLimit}
+	case holderRemovedEventType:
+		var customerId gocql.UUID
+		customerId, err = getUUIDValue(payload, "customerId")
+		e = domain.HolderRemovedEvent{AccountId: event.AccountId, EventId: event.EventId, CustomerId: customerId}
+	case standingOrderCreatedEventType:
+		e, err = deserializeStandingOrderCreatedEvent(event.AccountId, event.EventId, payload)
+	case standingOrderAmendedEventType:
+		e, err = deserializeStandingOrderAmendedEvent(event.AccountId, event.EventId, payload)
+	case standingOrderCancelledEventType:
+		e, err = deserializeStandingOrderCancelledEvent(event.AccountId, event.EventId, payload)
+	case standingOrderExecutedEventType:
+		e, err = deserializeStandingOrderExecutedEvent(event.AccountId, event.EventId, payload)
+	case standingOrderFailedEventType:
+		e, err = deserializeStandingOrderFailedEvent(event.AccountId, event.EventId, payload)
+	case mandateCreatedEventType:
+		e, err = deserializeMandateCreatedEvent(event.AccountId, event.EventId, payload)
+	case mandateRevokedEventType:
+		e, err = deserializeMandateRevokedEvent(event.AccountId, event.EventId, payload)
+	case directDebitCollectedEventType:
+		e, err = deserializeDirectDebitCollectedEvent(event.AccountId, event.EventId, payload)
+	case directDebitRefundedEventType:
+		e, err = deserializeDirectDebitRefundedEvent(event.AccountId, event.EventId, payload)
+	default:
+		return nil, fmt.Errorf("%s is not a known event type", eventType)
+	}
+	if err != nil {
+		return nil, errors.Join(fmt.Errorf("event %s", event.EventId.String()), err)
+	}
+	return e, nil
+}
+
+func deserializeEvents(loadedEvents []PersistableAccountEvent) ([]domain.AccountEvent, error) {
+	var deserEvents []domain.AccountEvent
+	for _, event := range loadedEvents {
+		e, err := deserializeEvent(event)
+		if err != nil {
+			return deserEvents, err
+		}
+		deserEvents = append(deserEvents, e)
+	}
+	return deserEvents, nil
+}
+
+func deserializeAccountCreatedEvent(accountId, eventId gocql.UUID, payload map[string]interface{}) (domain.AccountCreatedEvent, error) {
+	e := domain.AccountCreatedEvent{
+		AccountId: accountId,
+		EventId:   eventId,
+	}
+	pending, err := getOptionalValue[bool](payload, "pending")
+	if err != nil {
+		return e, err
+	}
+	e.Pending = pending
+	currency, err := getOptionalValue[string](payload, "currency")
+	if err != nil {
+		return e, err
+	}
+	e.Currency = domain.Currency(currency)
+	accountType, err := getOptionalValue[string](payload, "accountType")
+	if err != nil {
+		return e, err
+	}
+	e.Type = domain.AccountType(accountType)
+	if _, ok := payload["customerId"]; ok {
+		customerId, err := getUUIDValue(payload, "customerId")
+		if err != nil {
+			return e, err
+		}
+		e.CustomerId = customerId
+	}
+	accountNumber, err := getOptionalValue[string](payload, "accountNumber")
+	if err != nil {
+		return e, err
+	}
+	e.AccountNumber = domain.AccountNumber(accountNumber)
+	return e, nil
+}
+
+func deserializeAccountFrozenEvent(accountId, eventId gocql.UUID, payload map[string]interface{}) (domain.AccountFrozenEvent, error) {
+	e := domain.AccountFrozenEvent{
+		AccountId: accountId,
+		EventId:   eventId,
+	}
+	reason, err := getTypedValue[string](payload, "reason")
+	if err != nil {
+		return e, err
+	}
+	e.Reason = reason
+	return e, nil
+}
+
+func deserializeAccountClosedEvent(accountId, eventId gocql.UUID, payload map[string]interface{}) (domain.AccountClosedEvent, error) {
+	e := domain.AccountClosedEvent{
+		AccountId: accountId,
+		EventId:   eventId,
+	}
+	finalBalance, err := getTypedValue[float64](payload, "finalBalance")
+	if err != nil {
+		return e, err
+	}
+	e.FinalBalance = finalBalance
+	payoutDestination, err := getTypedValue[string](payload, "payoutDestination")
+	if err != nil {
+		return e, err
+	}
+	e.PayoutDestination = payoutDestination
+	return e, nil
+}
+
+func deserializeMoneyDipositedEvent(accountId, eventId gocql.UUID, payload map[string]interface{}) (domain.MoneyDipositedEvent, error) {
+	e := domain.MoneyDipositedEvent{
+		AccountId: accountId,
+		EventId:   eventId,
+	}
+	amount, err := getTypedValue[float64](payload, "amount")
+	if err != nil {
+		return e, err
+	}
+	e.Amount = amount
+	currency, err := getOptionalValue[string](payload, "currency")
+	if err != nil {
+		return e, err
+	}
+	e.Currency = domain.Currency(currency)
+	details, err := getTransactionDetails(payload)
+	if err != nil {
+		return e, err
+	}
+	e.Details = details
+	return e, nil
+}
+
+func deserializeMoneyWithdrawnEvent(accountId, eventId gocql.UUID, payload map[string]interface{}) (domain.MoneyWithdrawnEvent, error) {
+	e := domain.MoneyWithdrawnEvent{
+		AccountId: accountId,
+		EventId:   eventId,
+	}
+	amount, err := getTypedValue[float64](payload, "amount")
+	if err != nil {
+		return e, err
+	}
+	e.Amount = amount
+	currency, err := getOptionalValue[string](payload, "currency")
+	if err != nil {
+		return e, err
+	}
+	e.Currency = domain.Currency(currency)
+	details, err := getTransactionDetails(payload)
+	if err != nil {
+		return e, err
+	}
+	e.Details = details
+	return e, nil
+}
+
+func deserializeLimitSetEvent(accountId, eventId gocql.UUID, payload map[string]interface{}) (domain.LimitSetEvent, error) {
+	e := domain.LimitSetEvent{
+		AccountId: accountId,
+		EventId:   eventId,
+	}
+	limit, err := getTypedValue[float64](payload, "limit")
+	if err != nil {
+		return e, err
+	}
+	e.Limit = limit
+	return e, nil
+}
+
+func deserializeHoldPlacedEvent(accountId, eventId gocql.UUID, payload map[string]interface{}) (domain.HoldPlacedEvent, error) {
+	e := domain.HoldPlacedEvent{
+		AccountId: accountId,
+		EventId:   eventId,
+	}
+	holdId, err := getUUIDValue(payload, "holdId")
+	if err != nil {
+		return e, err
+	}
+	e.HoldId = holdId
+	amount, err := getTypedValue[float64](payload, "amount")
+	if err != nil {
+		return e, err
+	}
+	e.Amount = amount
+	expiresAt, err := getTimeValue(payload, "expiresAt")
+	if err != nil {
+		return e, err
+	}
+	e.ExpiresAt = expiresAt
+	return e, nil
+}
+
+func deserializeHoldCapturedEvent(accountId, eventId gocql.UUID, payload map[string]interface{}) (domain.HoldCapturedEvent, error) {
+	e := domain.HoldCapturedEvent{
+		AccountId: accountId,
+		EventId:   eventId,
+	}
+	holdId, err := getUUIDValue(payload, "holdId")
+	if err != nil {
+		return e, err
+	}
+	e.HoldId = holdId
+	amount, err := getTypedValue[float64](payload, "amount")
+	if err != nil {
+		return e, err
+	}
+	e.Amount = amount
+	return e, nil
+}
+
+func deserializeHoldReleasedEvent(accountId, eventId gocql.UUID, payload map[string]interface{}) (domain.HoldReleasedEvent, error) {
+	e := domain.HoldReleasedEvent{
+		AccountId: accountId,
+		EventId:   eventId,
+	}
+	holdId, err := getUUIDValue(payload, "holdId")
+	if err != nil {
+		return e, err
+	}
+	e.HoldId = holdId
+	return e, nil
+}
+
+func deserializeHoldExpiredEvent(accountId, eventId gocql.UUID, payload map[string]interface{}) (domain.HoldExpiredEvent, error) {
+	e := domain.HoldExpiredEvent{
+		AccountId: accountId,
+		EventId:   eventId,
+	}
+	holdId, err := getUUIDValue(payload, "holdId")
+	if err != nil {
+		return e, err
+	}
+	e.HoldId = holdId
+	return e, nil
+}
+
+func deserializeLimitPoliciesSetEvent(accountId, eventId gocql.UUID, payload map[string]interface{}) (domain.LimitPoliciesSetEvent, error) {
+	e := domain.LimitPoliciesSetEvent{
+		AccountId: accountId,
+		EventId:   eventId,
+	}
+	maxDailyWithdrawal, err := getTypedValue[float64](payload, "maxDailyWithdrawal")
+	if err != nil {
+		return e, err
+	}
+	e.Policies.MaxDailyWithdrawal = maxDailyWithdrawal
+	maxHourlyWithdrawals, err := getTypedValue[float64](payload, "maxHourlyWithdrawals")
+	if err != nil {
+		return e, err
+	}
+	e.Policies.MaxHourlyWithdrawals = int(maxHourlyWithdrawals)
+	maxTransactionAmount, err := getTypedValue[float64](payload, "maxTransactionAmount")
+	if err != nil {
+		return e, err
+	}
+	e.Policies.MaxTransactionAmount = maxTransactionAmount
+	return e, nil
+}
+
+func deserializeRuleDecisionRecordedEvent(accountId, eventId gocql.UUID, payload map[string]interface{}) (domain.RuleDecisionRecordedEvent, error) {
+	e := domain.RuleDecisionRecordedEvent{
+		AccountId: accountId,
+		EventId:   eventId,
+	}
+	command, err := getTypedValue[string](payload, "command")
+	if err != nil {
+		return e, err
+	}
+	e.Command = domain.TransactionCommand(command)
+	amount, err := getTypedValue[float64](payload, "amount")
+	if err != nil {
+		return e, err
+	}
+	e.Amount = amount
+	outcome, err := getTypedValue[string](payload, "outcome")
+	if err != nil {
+		return e, err
+	}
+	e.Outcome = domain.RuleOutcome(outcome)
+	rule, err := getOptionalValue[string](payload, "rule")
+	if err != nil {
+		return e, err
+	}
+	e.Rule = rule
+	ruleVersion, err := getOptionalValue[float64](payload, "ruleVersion")
+	if err != nil {
+		return e, err
+	}
+	e.RuleVersion = int(ruleVersion)
+	return e, nil
+}
+
+func deserializeApprovalRequestedEvent(accountId, eventId gocql.UUID, payload map[string]interface{}) (domain.ApprovalRequestedEvent, error) {
+	e := domain.ApprovalRequestedEvent{
+		AccountId: accountId,
+		EventId:   eventId,
+	}
+	requestId, err := getUUIDValue(payload, "requestId")
+	if err != nil {
+		return e, err
+	}
+	e.RequestId = requestId
+	command, err := getTypedValue[string](payload, "command")
+	if err != nil {
+		return e, err
+	}
+	e.Command = domain.ApprovalCommand(command)
+	amount, err := getTypedValue[float64](payload, "amount")
+	if err != nil {
+		return e, err
+	}
+	e.Amount = amount
+	limit, err := getTypedValue[float64](payload, "limit")
+	if err != nil {
+		return e, err
+	}
+	e.Limit = limit
+	requestedBy, err := getTypedValue[string](payload, "requestedBy")
+	if err != nil {
+		return e, err
+	}
+	e.RequestedBy = requestedBy
+	expiresAt, err := getTimeValue(payload, "expiresAt")
+	if err != nil {
+		return e, err
+	}
+	e.ExpiresAt = expiresAt
+	details, err := getTransactionDetails(payload)
+	if err != nil {
+		return e, err
+	}
+	e.Details = details
+	return e, nil
+}
+
+func deserializeApprovalGrantedEvent(accountId, eventId gocql.UUID, payload map[string]interface{}) (domain.ApprovalGrantedEvent, error) {
+	e := domain.ApprovalGrantedEvent{
+		AccountId: accountId,
+		EventId:   eventId,
+	}
+	requestId, err := getUUIDValue(payload, "requestId")
+	if err != nil {
+		return e, err
+	}
+	e.RequestId = requestId
+	approvedBy, err := getTypedValue[string](payload, "approvedBy")
+	if err != nil {
+		return e, err
+	}
+	e.ApprovedBy = approvedBy
+	return e, nil
+}
+
+func deserializeApprovalRejectedEvent(accountId, eventId gocql.UUID, payload map[string]interface{}) (domain.ApprovalRejectedEvent, error) {
+	e := domain.ApprovalRejectedEvent{
+		AccountId: accountId,
+		EventId:   eventId,
+	}
+	requestId, err := getUUIDValue(payload, "requestId")
+	if err != nil {
+		return e, err
+	}
+	e.RequestId = requestId
+	rejectedBy, err := getTypedValue[string](payload, "rejectedBy")
+	if err != nil {
+		return e, err
+	}
+	e.RejectedBy = rejectedBy
+	reason, err := getOptionalValue[string](payload, "reason")
+	if err != nil {
+		return e, err
+	}
+	e.Reason = reason
+	return e, nil
+}
+
+func deserializeApprovalExpiredEvent(accountId, eventId gocql.UUID, payload map[string]interface{}) (domain.ApprovalExpiredEvent, error) {
+	e := domain.ApprovalExpiredEvent{
+		AccountId: accountId,
+		EventId:   eventId,
+	}
+	requestId, err := getUUIDValue(payload, "requestId")
+	if err != nil {
+		return e, err
+	}
+	e.RequestId = requestId
+	return e, nil
+}
+
+func deserializeTransactionReversedEvent(accountId, eventId gocql.UUID, payload map[string]interface{}) (domain.TransactionReversedEvent, error) {
+	e := domain.TransactionReversedEvent{
+		AccountId: accountId,
+		EventId:   eventId,
+	}
+	reversedEventId, err := getUUIDValue(payload, "reversedEventId")
+	if err != nil {
+		return e, err
+	}
+	e.ReversedEventId = reversedEventId
+	command, err := getTypedValue[string](payload, "command")
+	if err != nil {
+		return e, err
+	}
+	e.Command = domain.TransactionCommand(command)
+	amount, err := getTypedValue[float64](payload, "amount")
+	if err != nil {
+		return e, err
+	}
+	e.Amount = amount
+	reason, err := getOptionalValue[string](payload, "reason")
+	if err != nil {
+		return e, err
+	}
+	e.Reason = reason
+	return e, nil
+}
+
+func deserializeInterestRateSetEvent(accountId, eventId gocql.UUID, payload map[string]interface{}) (domain.InterestRateSetEvent, error) {
+	e := domain.InterestRateSetEvent{
+		AccountId: accountId,
+		EventId:   eventId,
+	}
+	rate, err := getTypedValue[float64](payload, "rate")
+	if err != nil {
+		return e, err
+	}
+	e.Rate = rate
+	dayCount, err := getTypedValue[string](payload, "dayCount")
+	if err != nil {
+		return e, err
+	}
+	e.DayCount = domain.DayCountConvention(dayCount)
+	return e, nil
+}
+
+func deserializeInterestAccruedEvent(accountId, eventId gocql.UUID, payload map[string]interface{}) (domain.InterestAccruedEvent, error) {
+	e := domain.InterestAccruedEvent{
+		AccountId: accountId,
+		EventId:   eventId,
+	}
+	from, err := getDateValue(payload, "from")
+	if err != nil {
+		return e, err
+	}
+	e.From = from
+	through, err := getDateValue(payload, "through")
+	if err != nil {
+		return e, err
+	}
+	e.Through = through
+	amount, err := getTypedValue[float64](payload, "amount")
+	if err != nil {
+		return e, err
+	}
+	e.Amount = amount
+	rate, err := getTypedValue[float64](payload, "rate")
+	if err != nil {
+		return e, err
+	}
+	e.Rate = rate
+	dayCount, err := getTypedValue[string](payload, "dayCount")
+	if err != nil {
+		return e, err
+	}
+	e.DayCount = domain.DayCountConvention(dayCount)
+	debitAmount, err := getOptionalValue[float64](payload, "debitAmount")
+	if err != nil {
+		return e, err
+	}
+	e.DebitAmount = debitAmount
+	debitRate, err := getOptionalValue[float64](payload, "debitRate")
+	if err != nil {
+		return e, err
+	}
+	e.DebitRate = debitRate
+	return e, nil
+}
+
+func deserializeInterestCreditedEvent(accountId, eventId gocql.UUID, payload map[string]interface{}) (domain.InterestCreditedEvent, error) {
+	e := domain.InterestCreditedEvent{
+		AccountId: accountId,
+		EventId:   eventId,
+	}
+	amount, err := getTypedValue[float64](payload, "amount")
+	if err != nil {
+		return e, err
+	}
+	e.Amount = amount
+	month, err := getTypedValue[string](payload, "month")
+	if err != nil {
+		return e, err
+	}
+	e.Month = month
+	return e, nil
+}
+
+func deserializeFeeChargedEvent(accountId, eventId gocql.UUID, payload map[string]interface{}) (domain.FeeChargedEvent, error) {
+	e := domain.FeeChargedEvent{
+		AccountId: accountId,
+		EventId:   eventId,
+	}
+	kind, err := getTypedValue[string](payload, "kind")
+	if err != nil {
+		return e, err
+	}
+	e.Kind = domain.FeeKind(kind)
+	amount, err := getTypedValue[float64](payload, "amount")
+	if err != nil {
+		return e, err
+	}
+	e.Amount = amount
+	currency, err := getTypedValue[string](payload, "currency")
+	if err != nil {
+		return e, err
+	}
+	e.Currency = domain.Currency(currency)
+	month, err := getTypedValue[string](payload, "month")
+	if err != nil {
+		return e, err
+	}
+	e.Month = month
+	return e, nil
+}
+
+func deserializeStandingOrderCreatedEvent(accountId, eventId gocql.UUID, payload map[string]interface{}) (domain.StandingOrderCreatedEvent, error) {
+	e := domain.StandingOrderCreatedEvent{
+		AccountId: accountId,
+		EventId:   eventId,
+	}
+	orderId, err := getUUIDValue(payload, "orderId")
+	if err != nil {
+		return e, err
+	}
+	e.OrderId = orderId
+	if _, ok := payload["toAccountId"]; ok {
+		toAccountId, err := getUUIDValue(payload, "toAccountId")
+		if err != nil {
+			return e, err
+		}
+		e.ToAccountId = toAccountId
+	}
+	amount, err := getTypedValue[float64](payload, "amount")
+	if err != nil {
+		return e, err
+	}
+	e.Amount = amount
+	interval, err := getTypedValue[string](payload, "interval")
+	if err != nil {
+		return e, err
+	}
+	e.Interval = domain.StandingOrderInterval(interval)
+	startDate, err := getDateValue(payload, "startDate")
+	if err != nil {
+		return e, err
+	}
+	e.StartDate = startDate
+	endDate, err := getOptionalDateValue(payload, "endDate")
+	if err != nil {
+		return e, err
+	}
+	e.EndDate = endDate
+	details, err := getTransactionDetails(payload)
+	if err != nil {
+		return e, err
+	}
+	e.Details = details
+	return e, nil
+}
+
+func deserializeStandingOrderAmendedEvent(accountId, eventId gocql.UUID, payload map[string]interface{}) (domain.StandingOrderAmendedEvent, error) {
+	e := domain.StandingOrderAmendedEvent{
+		AccountId: accountId,
+		EventId:   eventId,
+	}
+	orderId, err := getUUIDValue(payload, "orderId")
+	if err != nil {
+		return e, err
+	}
+	e.OrderId = orderId
+	amount, err := getTypedValue[float64](payload, "amount")
+	if err != nil {
+		return e, err
+	}
+	e.Amount = amount
+	interval, err := getTypedValue[string](payload, "interval")
+	if err != nil {
+		return e, err
+	}
+	e.Interval = domain.StandingOrderInterval(interval)
+	nextExecution, err := getDateValue(payload, "nextExecution")
+	if err != nil {
+		return e, err
+	}
+	e.NextExecution = nextExecution
+	endDate, err := getOptionalDateValue(payload, "endDate")
+	if err != nil {
+		return e, err
+	}
+	e.EndDate = endDate
+	details, err := getTransactionDetails(payload)
+	if err != nil {
+		return e, err
+	}
+	e.Details = details
+	return e, nil
+}
+
+func deserializeStandingOrderCancelledEvent(accountId, eventId gocql.UUID, payload map[string]interface{}) (domain.StandingOrderCancelledEvent, error) {
+	e := domain.StandingOrderCancelledEvent{
+		AccountId: accountId,
+		EventId:   eventId,
+	}
+	orderId, err := getUUIDValue(payload, "orderId")
+	if err != nil {
+		return e, err
+	}
+	e.OrderId = orderId
+	reason, err := getOptionalValue[string](payload, "reason")
+	if err != nil {
+		return e, err
+	}
+	e.Reason = reason
+	return e, nil
+}
+
+func deserializeStandingOrderExecutedEvent(accountId, eventId gocql.UUID, payload map[string]interface{}) (domain.StandingOrderExecutedEvent, error) {
+	e := domain.StandingOrderExecutedEvent{
+		AccountId: accountId,
+		EventId:   eventId,
+	}
+	orderId, err := getUUIDValue(payload, "orderId")
+	if err != nil {
+		return e, err
+	}
+	e.OrderId = orderId
+	dueDate, err := getDateValue(payload, "dueDate")
+	if err != nil {
+		return e, err
+	}
+	e.DueDate = dueDate
+	amount, err := getTypedValue[float64](payload, "amount")
+	if err != nil {
+		return e, err
+	}
+	e.Amount = amount
+	transactionId, err := getUUIDValue(payload, "transactionId")
+	if err != nil {
+		return e, err
+	}
+	e.TransactionId = transactionId
+	nextExecution, err := getOptionalDateValue(payload, "nextExecution")
+	if err != nil {
+		return e, err
+	}
+	e.NextExecution = nextExecution
+	return e, nil
+}
+
+func deserializeStandingOrderFailedEvent(accountId, eventId gocql.UUID, payload map[string]interface{}) (domain.StandingOrderFailedEvent, error) {
+	e := domain.StandingOrderFailedEvent{
+		AccountId: accountId,
+		EventId:   eventId,
+	}
+	orderId, err := getUUIDValue(payload, "orderId")
+	if err != nil {
+		return e, err
+	}
+	e.OrderId = orderId
+	dueDate, err := getDateValue(payload, "dueDate")
+	if err != nil {
+		return e, err
+	}
+	e.DueDate = dueDate
+	attempt, err := getTypedValue[float64](payload, "attempt")
+	if err != nil {
+		return e, err
+	}
+	e.Attempt = int(attempt)
+	reason, err := getTypedValue[string](payload, "reason")
+	if err != nil {
+		return e, err
+	}
+	e.Reason = reason
+	if _, ok := payload["retryAt"]; ok {
+		retryAt, err := getTimeValue(payload, "retryAt")
+		if err != nil {
+			return e, err
+		}
+		e.RetryAt = retryAt
+	}
+	nextExecution, err := getOptionalDateValue(payload, "nextExecution")
+	if err != nil {
+		return e, err
+	}
+	e.NextExecution = nextExecution
+	return e, nil
+}
+
+func deserializeMandateCreatedEvent(accountId, eventId gocql.UUID, payload map[string]interface{}) (domain.MandateCreatedEvent, error) {
+	e := domain.MandateCreatedEvent{
+		AccountId: accountId,
+		EventId:   eventId,
+	}
+	mandateId, err := getUUIDValue(payload, "mandateId")
+	if err != nil {
+		return e, err
+	}
+	e.MandateId = mandateId
+	for key, field := range map[string]*string{
+		"creditorId":   &e.CreditorId,
+		"creditorName": &e.CreditorName,
+		"reference":    &e.Reference,
+	} {
+		value, err := getTypedValue[string](payload, key)
+		if err != nil {
+			return e, err
+		}
+		*field = value
+	}
+	for key, field := range map[string]*float64{
+		"maxAmount":        &e.MaxAmount,
+		"maxMonthlyAmount": &e.MaxMonthlyAmount,
+	} {
+		value, err := getOptionalValue[float64](payload, key)
+		if err != nil {
+			return e, err
+		}
+		*field = value
+	}
+	return e, nil
+}
+
+func deserializeMandateRevokedEvent(accountId, eventId gocql.UUID, payload map[string]interface{}) (domain.MandateRevokedEvent, error) {
+	e := domain.MandateRevokedEvent{
+		AccountId: accountId,
+		EventId:   eventId,
+	}
+	mandateId, err := getUUIDValue(payload, "mandateId")
+	if err != nil {
+		return e, err
+	}
+	e.MandateId = mandateId
+	reason, err := getOptionalValue[string](payload, "reason")
+	if err != nil {
+		return e, err
+	}
+	e.Reason = reason
+	return e, nil
+}
+
+func deserializeDirectDebitCollectedEvent(accountId, eventId gocql.UUID, payload map[string]interface{}) (domain.DirectDebitCollectedEvent, error) {
+	e := domain.DirectDebitCollectedEvent{
+		AccountId: accountId,
+		EventId:   eventId,
+	}
+	mandateId, err := getUUIDValue(payload, "mandateId")
+	if err != nil {
+		return e, err
+	}
+	e.MandateId = mandateId
+	amount, err := getTypedValue[float64](payload, "amount")
+	if err != nil {
+		return e, err
+	}
+	e.Amount = amount
+	currency, err := getTypedValue[string](payload, "currency")
+	if err != nil {
+		return e, err
+	}
+	e.Currency = domain.Currency(currency)
+	details, err := getTransactionDetails(payload)
+	if err != nil {
+		return e, err
+	}
+	e.Details = details
+	return e, nil
+}
+
+func deserializeDirectDebitRefundedEvent(accountId, eventId gocql.UUID, payload map[string]interface{}) (domain.DirectDebitRefundedEvent, error) {
+	e := domain.DirectDebitRefundedEvent{
+		AccountId: accountId,
+		EventId:   eventId,
+	}
+	collectionId, err := getUUIDValue(payload, "collectionId")
+	if err != nil {
+		return e, err
+	}
+	e.CollectionId = collectionId
+	mandateId, err := getUUIDValue(payload, "mandateId")
+	if err != nil {
+		return e, err
+	}
+	e.MandateId = mandateId
+	amount, err := getTypedValue[float64](payload, "amount")
+	if err != nil {
+		return e, err
+	}
+	e.Amount = amount
+	reason, err := getOptionalValue[string](payload, "reason")
+	if err != nil {
+		return e, err
+	}
+	e.Reason = reason
+	return e, nil
+}
+
+func holderFields(customerId gocql.UUID, permission domain.HolderPermission, withdrawalLimit float64) map[string]interface{} {
+	return map[string]interface{}{
+		"customerId":      customerId,
+		"permission":      permission,
+		"withdrawalLimit": withdrawalLimit,
+	}
+}
+
+func getHolderValue(payload map[string]interface{}) (domain.Holder, error) {
+	holder := domain.Holder{}
+	customerId, err := getUUIDValue(payload, "customerId")
+	if err != nil {
+		return holder, err
+	}
+	holder.CustomerId = customerId
+	permission, err := getTypedValue[string](payload, "permission")
+	if err != nil {
+		return holder, err
+	}
+	holder.Permission = domain.HolderPermission(permission)
+	withdrawalLimit, err := getOptionalValue[float64](payload, "withdrawalLimit")
+	if err != nil {
+		return holder, err
+	}
+	holder.WithdrawalLimit = withdrawalLimit
+	return holder, nil
+}
+
+type exchangeEvent domain.ExchangeDebitedEvent
+
+func exchangeFields(e exchangeEvent) map[string]interface{} {
+	return map[string]interface{}{
+		"exchangeId":       e.ExchangeId,
+		"amount":           e.Amount,
+		"currency":         e.Currency,
+		"counterAccountId": e.CounterAccountId,
+		"counterAmount":    e.CounterAmount,
+		"counterCurrency":  e.CounterCurrency,
+		"rate":             e.Rate,
+		"spread":           e.Spread,
+		"snapshotId":       e.SnapshotId,
+	}
+}
+
+func deserializeExchangeEvent(accountId, eventId gocql.UUID, payload map[string]interface{}) (exchangeEvent, error) {
+	e := exchangeEvent{
+		AccountId: accountId,
+		EventId:   eventId,
+	}
+	exchangeId, err := getUUIDValue(payload, "exchangeId")
+	if err != nil {
+		return e, err
+	}
+	e.ExchangeId = exchangeId
+	amount, err := getTypedValue[float64](payload, "amount")
+	if err != nil {
+		return e, err
+	}
+	e.Amount = amount
+	currency, err := getTypedValue[string](payload, "currency")
+	if err != nil {
+		return e, err
+	}
+	e.Currency = domain.Currency(currency)
+	counterAccountId, err := getUUIDValue(payload, "counterAccountId")
+	if err != nil {
+		return e, err
+	}
+	e.CounterAccountId = counterAccountId
+	counterAmount, err := getTypedValue[float64](payload, "counterAmount")
+	if err != nil {
+		return e, err
+	}
+	e.CounterAmount = counterAmount
+	counterCurrency, err := getTypedValue[string](payload, "counterCurrency")
+	if err != nil {
+		return e, err
+	}
+	e.CounterCurrency = domain.Currency(counterCurrency)
+	rate, err := getTypedValue[float64](payload, "rate")
+	if err != nil {
+		return e, err
+	}
+	e.Rate = rate
+	spread, err := getTypedValue[float64](payload, "spread")
+	if err != nil {
+		return e, err
+	}
+	e.Spread = spread
+	snapshotId, err := getUUIDValue(payload, "snapshotId")
+	if err != nil {
+		return e, err
+	}
+	e.SnapshotId = snapshotId
+	return e, nil
+}
+
+func withDetails(fields map[string]interface{}, details domain.TransactionDetails) map[string]interface{} {
+	for key, value := range map[string]string{
+		"reference":           details.Reference,
+		"description":         details.Description,
+		"counterpartyName":    details.CounterpartyName,
+		"counterpartyAccount": details.CounterpartyAccount,
+		"category":            details.Category,
+	} {
+		if value != "" {
+			fields[key] = value
+		}
+	}
+	return fields
+}
+
+func getTransactionDetails(payload map[string]interface{}) (domain.TransactionDetails, error) {
+	details := domain.TransactionDetails{}
+	for key, field := range map[string]*string{
+		"reference":           &details.Reference,
+		"description":         &details.Description,
+		"counterpartyName":    &details.CounterpartyName,
+		"counterpartyAccount": &details.CounterpartyAccount,
+		"category":            &details.Category,
+	} {
+		value, err := getOptionalValue[string](payload, key)
+		if err != nil {
+			return details, err
+		}
+		*field = value
+	}
+	return details, nil
+}
+
+func getTypedValue[T any](payload map[string]interface{}, key string) (T, error) {
+	var value T
+	valueS, ok := payload[key]
+	if !ok {
+		return value, fmt.Errorf("%s is not set", key)
+	}
+	value, ok = valueS.(T)
+	if !ok {
+		return value, fmt.Errorf("%v is not of type %T", value, value)
+	}
+	return value, nil
+}
+
+func getOptionalValue[T any](payload map[string]interface{}, key string) (T, error) {
+	var value T
+	if _, ok := payload[key]; !ok {
+		return value, nil
+	}
+	return getTypedValue[T](payload, key)
+}
+
+func getUUIDValue(payload map[string]interface{}, key string) (gocql.UUID, error) {
+	value, err := getTypedValue[string](payload, key)
+	if err != nil {
+		return gocql.UUID{}, err
+	}
+	return gocql.ParseUUID(value)
+}
+
+func getTimeValue(payload map[string]interface{}, key string) (time.Time, error) {
+	value, err := getTypedValue[string](payload, key)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Parse(time.RFC3339Nano, value)
+}
+
+func getDateValue(payload map[string]interface{}, key string) (time.Time, error) {
+	value, err := getTypedValue[string](payload, key)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Parse(dateLayout, value)
+}
+
+func withOptionalDate(fields map[string]interface{}, key string, date time.Time) map[string]interface{} {
+	if !date.IsZero() {
+		fields[key] = date.Format(dateLayout)
+	}
+	return fields
+}
+
+func getOptionalDateValue(payload map[string]interface{}, key string) (time.Time, error) {
+	if _, ok := payload[key]; !ok {
+		return time.Time{}, nil
+	}
+	return getDateValue(payload, key)
+}
+
+func marshalPayload[T ~string](eventType T, fields map[string]interface{}) (string, error) {
+	fields["eventType"] = eventType
+	payload, err := json.Marshal(fields)
+	if err != nil {
+		return "", err
+	}
+	return string(payload), nil
+}
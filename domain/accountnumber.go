@@ -0,0 +1,148 @@
+package domain
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/gocql/gocql"
+)
+
+const (
+	accountNumberDigits      = 10
+	accountNumberMaxAttempts = 5
+)
+
+type AccountNumber string
+
+type AccountNumbering struct {
+	Country  string
+	BankCode string
+}
+
+var DefaultAccountNumbering = AccountNumbering{Country: "DE", BankCode: "10010010"}
+
+func (n AccountNumbering) Validate() error {
+	if len(n.Country) != 2 || !isUpperAlpha(n.Country) {
+		return NewDomainError("country code %s must be two upper case letters", n.Country)
+	}
+	if n.BankCode == "" || len(n.BankCode) > 20 || !isDigits(n.BankCode) {
+		return NewDomainError("bank code %s must consist of 1 to 20 digits", n.BankCode)
+	}
+	return nil
+}
+
+func (n AccountNumbering) generate() (AccountNumber, error) {
+	max := new(big.Int).Exp(big.NewInt(10), big.NewInt(accountNumberDigits), nil)
+	value, err := rand.Int(rand.Reader, max)
+	if err != nil {
+		return "", err
+	}
+	bban := fmt.Sprintf("%s%0*d", n.BankCode, accountNumberDigits, value)
+	check := 98 - mod97(bban+n.Country+"00")
+	return AccountNumber(fmt.Sprintf("%s%02d%s", n.Country, check, bban)), nil
+}
+
+func ParseAccountNumber(value string) (AccountNumber, error) {
+	number := strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(value), " ", ""))
+	if len(number) < 5 || len(number) > 34 || !isUpperAlpha(number[:2]) || !isDigits(number[2:4]) || !isAlphanumeric(number[4:]) {
+		return "", NewDomainError("%s is not a valid account number", value)
+	}
+	if mod97(number[4:]+number[:4]) != 1 {
+		return "", NewDomainError("account number %s has an invalid checksum", value)
+	}
+	return AccountNumber(number), nil
+}
+
+func mod97(value string) int {
+	remainder := 0
+	for _, r := range value {
+		if r >= 'A' && r <= 'Z' {
+			remainder = (remainder*100 + int(r-'A') + 10) % 97
+		} else {
+			remainder = (remainder*10 + int(r-'0')) % 97
+		}
+	}
+	return remainder
+}
+
+func isUpperAlpha(value string) bool {
+	for _, r := range value {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+func isDigits(value string) bool {
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func isAlphanumeric(value string) bool {
+	for _, r := range value {
+		if (r < '0' || r > '9') && (r < 'A' || r > 'Z') {
+			return false
+		}
+	}
+	return true
+}
+
+func (a *Account) AccountNumber() AccountNumber {
+	return a.accountNumber
+}
+
+func (s *AccountService) assignAccountNumber(ctx context.Context, accountId gocql.UUID) (AccountNumber, error) {
+	if s.numbers == nil {
+		return "", nil
+	}
+	for attempt := 0; attempt < accountNumberMaxAttempts; attempt++ {
+		number, err := s.numbering.generate()
+		if err != nil {
+			return "", err
+		}
+		registered, err := s.numbers.Register(ctx, number, accountId)
+		if err != nil {
+			return "", err
+		}
+		if registered {
+			return number, nil
+		}
+	}
+	return "", fmt.Errorf("no free account number found after %d attempts", accountNumberMaxAttempts)
+}
+
+func (s *AccountService) releaseAccountNumber(ctx context.Context, number AccountNumber, accountId gocql.UUID) error {
+	if s.numbers == nil || number == "" {
+		return nil
+	}
+	return s.numbers.Release(ctx, number, accountId)
+}
+
+func (s *AccountService) ResolveAccountId(ctx context.Context, reference string) (gocql.UUID, error) {
+	if accountId, err := gocql.ParseUUID(reference); err == nil {
+		return accountId, nil
+	}
+	number, err := ParseAccountNumber(reference)
+	if err != nil {
+		return gocql.UUID{}, err
+	}
+	if s.numbers == nil {
+		return gocql.UUID{}, NewAccountNotFoundError("account %s does not exist", number)
+	}
+	accountId, ok, err := s.numbers.Lookup(ctx, number)
+	if err != nil {
+		return gocql.UUID{}, err
+	}
+	if !ok {
+		return gocql.UUID{}, NewAccountNotFoundError("account %s does not exist", number)
+	}
+	return accountId, nil
+}
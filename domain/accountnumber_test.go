@@ -0,0 +1,145 @@
+package domain
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gocql/gocql"
+)
+
+type memoryAccountNumberRepository struct {
+	numbers map[AccountNumber]gocql.UUID
+}
+
+func (r *memoryAccountNumberRepository) Register(ctx context.Context, number AccountNumber, accountId gocql.UUID) (bool, error) {
+	if _, ok := r.numbers[number]; ok {
+		return false, nil
+	}
+	r.numbers[number] = accountId
+	return true, nil
+}
+
+func (r *memoryAccountNumberRepository) Lookup(ctx context.Context, number AccountNumber) (gocql.UUID, bool, error) {
+	accountId, ok := r.numbers[number]
+	return accountId, ok, nil
+}
+
+func (r *memoryAccountNumberRepository) Release(ctx context.Context, number AccountNumber, accountId gocql.UUID) error {
+	if r.numbers[number] == accountId {
+		delete(r.numbers, number)
+	}
+	return nil
+}
+
+func TestMod97(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected int
+	}{
+		{"0", 0},
+		{"97", 0},
+		{"98", 1},
+		{"A", 10},
+		{"Z", 35},
+		{"370400440532013000DE89", 1},
+		{"NWBK60161331926819GB29", 1},
+		{"370400440532013000DE00", 9},
+	}
+	for _, test := range tests {
+		t.Run(test.value, func(t *testing.T) {
+			if got := mod97(test.value); got != test.expected {
+				t.Fatalf("expected %d, got %d", test.expected, got)
+			}
+		})
+	}
+}
+
+func TestGeneratedAccountNumbersAreValid(t *testing.T) {
+	numberings := []AccountNumbering{
+		DefaultAccountNumbering,
+		{Country: "GB", BankCode: "60161331"},
+		{Country: "NL", BankCode: "1"},
+	}
+	for _, numbering := range numberings {
+		t.Run(numbering.Country, func(t *testing.T) {
+			for i := 0; i < 100; i++ {
+				number, err := numbering.generate()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(number) != 4+len(numbering.BankCode)+accountNumberDigits || string(number[:2]) != numbering.Country {
+					t.Fatalf("generated number %s does not match the numbering %+v", number, numbering)
+				}
+				if parsed, err := ParseAccountNumber(string(number)); err != nil || parsed != number {
+					t.Fatalf("generated number %s is not valid: %v", number, err)
+				}
+			}
+		})
+	}
+}
+
+func TestParseAccountNumber(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected AccountNumber
+		valid    bool
+	}{
+		{"DE89370400440532013000", "DE89370400440532013000", true},
+		{" de89 3704 0044 0532 0130 00 ", "DE89370400440532013000", true},
+		{"GB29NWBK60161331926819", "GB29NWBK60161331926819", true},
+		{"DE88370400440532013000", "", false},
+		{"DE89370400440532013001", "", false},
+		{"1289370400440532013000", "", false},
+		{"DEXX370400440532013000", "", false},
+		{"DE89", "", false},
+		{"DE89-370400440532013000", "", false},
+		{"DE8937040044053201300012345678901234", "", false},
+	}
+	for _, test := range tests {
+		t.Run(test.value, func(t *testing.T) {
+			number, err := ParseAccountNumber(test.value)
+			if test.valid != (err == nil) {
+				t.Fatalf("expected valid %t, got %v", test.valid, err)
+			}
+			if number != test.expected {
+				t.Fatalf("expected %s, got %s", test.expected, number)
+			}
+		})
+	}
+}
+
+func TestResolveAccountId(t *testing.T) {
+	accountId := gocql.TimeUUID()
+	registered := AccountNumber("DE89370400440532013000")
+	numbers := &memoryAccountNumberRepository{numbers: map[AccountNumber]gocql.UUID{registered: accountId}}
+	tests := []struct {
+		name      string
+		numbers   AccountNumberRepository
+		reference string
+		expected  gocql.UUID
+		notFound  bool
+		invalid   bool
+	}{
+		{name: "account id", numbers: numbers, reference: accountId.String(), expected: accountId},
+		{name: "account id without numbers", reference: accountId.String(), expected: accountId},
+		{name: "registered number", numbers: numbers, reference: "DE89 3704 0044 0532 0130 00", expected: accountId},
+		{name: "unknown number", numbers: numbers, reference: "GB29NWBK60161331926819", notFound: true},
+		{name: "number without numbers", reference: string(registered), notFound: true},
+		{name: "invalid checksum", numbers: numbers, reference: "DE88370400440532013000", invalid: true},
+		{name: "neither id nor number", numbers: numbers, reference: "account-1", invalid: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			service := AccountService{numbers: test.numbers}
+			resolved, err := service.ResolveAccountId(context.Background(), test.reference)
+			_, notFound := err.(*AccountNotFoundError)
+			_, invalid := err.(*DomainError)
+			if notFound != test.notFound || invalid != test.invalid {
+				t.Fatalf("expected not found %t and invalid %t, got %v", test.notFound, test.invalid, err)
+			}
+			if resolved != test.expected {
+				t.Fatalf("expected %s, got %s", test.expected, resolved)
+			}
+		})
+	}
+}
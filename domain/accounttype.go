@@ -0,0 +1,100 @@
+package domain
+
+import (
+	"time"
+)
+
+type AccountType string
+
+const (
+	CheckingAccount AccountType = "checking"
+	SavingsAccount  AccountType = "savings"
+	BusinessAccount AccountType = "business"
+	EscrowAccount   AccountType = "escrow"
+)
+
+type AccountTypeProfile struct {
+	Overdraft             bool
+	Holds                 bool
+	MaxMonthlyWithdrawals int
+	WithdrawalApproval    bool
+	DefaultPolicies       LimitPolicies
+}
+
+var accountTypeProfiles = map[AccountType]AccountTypeProfile{
+	CheckingAccount: {
+		Overdraft:       true,
+		Holds:           true,
+		DefaultPolicies: LimitPolicies{MaxHourlyWithdrawals: 10},
+	},
+	SavingsAccount: {
+		MaxMonthlyWithdrawals: 6,
+	},
+	BusinessAccount: {
+		Overdraft: true,
+		Holds:     true,
+	},
+	EscrowAccount: {
+		WithdrawalApproval: true,
+		DefaultPolicies:    LimitPolicies{MaxHourlyWithdrawals: 1},
+	},
+}
+
+type monthlyWithdrawals struct {
+	month time.Time
+	count int
+}
+
+func ParseAccountType(value string) (AccountType, error) {
+	accountType := AccountType(value)
+	if accountType == "" {
+		return CheckingAccount, nil
+	}
+	if _, ok := accountTypeProfiles[accountType]; !ok {
+		return "", NewDomainError("%s is not a supported account type, use %s, %s, %s or %s", value, CheckingAccount, SavingsAccount, BusinessAccount, EscrowAccount)
+	}
+	return accountType, nil
+}
+
+func (t AccountType) Profile() AccountTypeProfile {
+	return accountTypeProfiles[t]
+}
+
+func (t AccountType) checkLimit(limit float64) error {
+	if limit < 0 && !t.Profile().Overdraft {
+		return NewDomainError("%s accounts can not be overdrawn, limit %f is not allowed", t, limit)
+	}
+	return nil
+}
+
+func (a *Account) Type() AccountType {
+	return a.accountType
+}
+
+func (a *Account) WithdrawalsThisMonth() int {
+	if !a.monthlyWithdrawals.month.Equal(startOfMonth(a.clock.Now())) {
+		return 0
+	}
+	return a.monthlyWithdrawals.count
+}
+
+func (a *Account) checkAccountType(action string) error {
+	profile := a.accountType.Profile()
+	if max := profile.MaxMonthlyWithdrawals; max > 0 && a.WithdrawalsThisMonth() >= max {
+		return NewDomainError("the maximum of %d withdrawals per month of %s accounts is reached, can not %s", max, a.accountType, action)
+	}
+	return nil
+}
+
+func (a *Account) countWithdrawal(at time.Time) {
+	month := startOfMonth(at)
+	if !a.monthlyWithdrawals.month.Equal(month) {
+		a.monthlyWithdrawals = monthlyWithdrawals{month: month}
+	}
+	a.monthlyWithdrawals.count++
+}
+
+func startOfMonth(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,171 @@
+package domain
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/gocql/gocql"
+)
+
+const DefaultApprovalExpiry = 24 * time.Hour
+
+type ApprovalCommand string
+
+const (
+	WithdrawApproval ApprovalCommand = "withdraw"
+	SetLimitApproval ApprovalCommand = "setLimit"
+)
+
+type ApprovalPolicy struct {
+	WithdrawalThreshold float64
+	LimitLowering       bool
+	Expiry              time.Duration
+}
+
+type PendingApproval struct {
+	RequestId   gocql.UUID
+	Command     ApprovalCommand
+	Amount      float64
+	Limit       float64
+	Details     TransactionDetails
+	RequestedBy string
+	ExpiresAt   time.Time
+}
+
+func (a *Account) PendingApprovals() []PendingApproval {
+	approvals := make([]PendingApproval, 0, len(a.approvals))
+	for _, approval := range a.approvals {
+		approvals = append(approvals, approval)
+	}
+	sort.Slice(approvals, func(i, j int) bool {
+		return approvals[i].ExpiresAt.Before(approvals[j].ExpiresAt)
+	})
+	return approvals
+}
+
+func (a *Account) ApproveRequest(ctx context.Context, requestId gocql.UUID) error {
+	approval, err := a.pendingApproval(requestId)
+	if err != nil {
+		return err
+	}
+	caller := CallerFrom(ctx)
+	if err := requireApprover(caller, "approve"); err != nil {
+		return err
+	}
+	if caller.Name == approval.RequestedBy {
+		return NewPermissionDeniedError("%s can not approve their own request %s", caller.Name, requestId)
+	}
+	if !approval.ExpiresAt.After(a.clock.Now()) {
+		return NewDomainError("request %s expired at %s", requestId, approval.ExpiresAt)
+	}
+	granted := ApprovalGrantedEvent{
+		AccountId:  a.accountId,
+		EventId:    gocql.TimeUUID(),
+		RequestId:  requestId,
+		ApprovedBy: caller.Name,
+	}
+	switch approval.Command {
+	case WithdrawApproval:
+		if err := a.checkWithdrawal(approval.Amount); err != nil {
+			return err
+		}
+		events := []AccountEvent{granted, MoneyWithdrawnEvent{
+			AccountId: a.accountId,
+			EventId:   gocql.TimeUUID(),
+			Amount:    approval.Amount,
+			Currency:  a.currency,
+			Details:   approval.Details,
+		}}
+		fees, _ := a.withdrawalFees(approval.Amount)
+		return a.emit(ctx, append(events, fees...)...)
+	case SetLimitApproval:
+		if err := a.checkLimit(approval.Limit); err != nil {
+			return err
+		}
+		return a.emit(ctx, granted, LimitSetEvent{
+			AccountId: a.accountId,
+			EventId:   gocql.TimeUUID(),
+			Limit:     approval.Limit,
+		})
+	default:
+		return NewDomainError("request %s has the unknown command %s", requestId, approval.Command)
+	}
+}
+
+func (a *Account) RejectRequest(ctx context.Context, requestId gocql.UUID, reason string) error {
+	if _, err := a.pendingApproval(requestId); err != nil {
+		return err
+	}
+	caller := CallerFrom(ctx)
+	if err := requireApprover(caller, "reject"); err != nil {
+		return err
+	}
+	return a.emit(ctx, ApprovalRejectedEvent{
+		AccountId:  a.accountId,
+		EventId:    gocql.TimeUUID(),
+		RequestId:  requestId,
+		RejectedBy: caller.Name,
+		Reason:     reason,
+	})
+}
+
+func (a *Account) ExpireApprovals(ctx context.Context) (int, error) {
+	now := a.clock.Now()
+	var events []AccountEvent
+	for _, approval := range a.PendingApprovals() {
+		if approval.ExpiresAt.After(now) {
+			break
+		}
+		events = append(events, ApprovalExpiredEvent{
+			AccountId: a.accountId,
+			EventId:   gocql.TimeUUID(),
+			RequestId: approval.RequestId,
+		})
+	}
+	if len(events) == 0 {
+		return 0, nil
+	}
+	return len(events), a.emit(ctx, events...)
+}
+
+func (a *Account) requestApproval(ctx context.Context, approval PendingApproval, decision *RuleDecisionRecordedEvent) error {
+	caller := CallerFrom(ctx)
+	if !caller.Authenticated() {
+		return NewPermissionDeniedError("%s requires approval and can only be requested by an authenticated user", approval.Command)
+	}
+	expiry := a.approval.Expiry
+	if expiry <= 0 {
+		expiry = DefaultApprovalExpiry
+	}
+	e := ApprovalRequestedEvent{
+		AccountId:   a.accountId,
+		EventId:     gocql.TimeUUID(),
+		RequestId:   gocql.MustRandomUUID(),
+		Command:     approval.Command,
+		Amount:      approval.Amount,
+		Limit:       approval.Limit,
+		Details:     approval.Details,
+		RequestedBy: caller.Name,
+		ExpiresAt:   a.clock.Now().Add(expiry),
+	}
+	if err := a.emit(ctx, withDecision(decision, e)...); err != nil {
+		return err
+	}
+	return NewApprovalRequiredError(a.approvals[e.RequestId], "%s on account %s requires approval by a second user", approval.Command, a.accountId)
+}
+
+func (a *Account) pendingApproval(requestId gocql.UUID) (PendingApproval, error) {
+	approval, ok := a.approvals[requestId]
+	if !ok {
+		return approval, NewDomainError("request %s is not pending on account %s", requestId, a.accountId)
+	}
+	return approval, nil
+}
+
+func requireApprover(caller Caller, action string) error {
+	if !caller.HasRole(ApproverRole) {
+		return NewPermissionDeniedError("only users with the %s role can %s requests", ApproverRole, action)
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"context"
+
+	"github.com/gocql/gocql"
+)
+
+const (
+	ApproverRole = "approver"
+	AdminRole    = "admin"
+)
+
+type Caller struct {
+	Name       string
+	Roles      []string
+	CustomerId gocql.UUID
+}
+
+type callerKey struct{}
+
+func WithCaller(ctx context.Context, caller Caller) context.Context {
+	return context.WithValue(ctx, callerKey{}, caller)
+}
+
+func CallerFrom(ctx context.Context) Caller {
+	caller, _ := ctx.Value(callerKey{}).(Caller)
+	return caller
+}
+
+func (c Caller) Authenticated() bool {
+	return c.Name != ""
+}
+
+func (c Caller) IsCustomer() bool {
+	return c.CustomerId != gocql.UUID{}
+}
+
+func (c Caller) HasRole(role string) bool {
+	for _, r := range c.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"math"
+	"strings"
+)
+
+type Currency string
+
+const DefaultCurrency Currency = "EUR"
+
+var minorUnits = map[Currency]int{
+	"AUD": 2, "BHD": 3, "BRL": 2, "CAD": 2, "CHF": 2, "CLP": 0, "CNY": 2, "CZK": 2,
+	"DKK": 2, "EUR": 2, "GBP": 2, "HKD": 2, "HUF": 2, "IDR": 2, "ILS": 2, "INR": 2,
+	"ISK": 0, "JOD": 3, "JPY": 0, "KRW": 0, "KWD": 3, "MXN": 2, "NOK": 2, "NZD": 2,
+	"OMR": 3, "PLN": 2, "RON": 2, "SEK": 2, "SGD": 2, "THB": 2, "TND": 3, "TRY": 2,
+	"UGX": 0, "USD": 2, "VND": 0, "ZAR": 2,
+}
+
+func ParseCurrency(code string) (Currency, error) {
+	currency := Currency(strings.ToUpper(strings.TrimSpace(code)))
+	if _, ok := minorUnits[currency]; !ok {
+		return "", NewDomainError("%q is not a supported ISO 4217 currency", code)
+	}
+	return currency, nil
+}
+
+func (c Currency) MinorUnits() int {
+	return minorUnits[c]
+}
+
+func (c Currency) checkAmount(amount float64) error {
+	scaled := amount * math.Pow10(c.MinorUnits())
+	if math.Abs(scaled-math.Round(scaled)) > 1e-6 {
+		return NewDomainError("amount %v has more than %d decimal places allowed for %s", amount, c.MinorUnits(), c)
+	}
+	return nil
+}
+
+func (a *Account) checkCurrency(amount float64, currency Currency) error {
+	if currency != a.currency {
+		return NewDomainError("%s can not be booked on account %s in %s", currency, a.accountId, a.currency)
+	}
+	return a.currency.checkAmount(amount)
+}
@@ -0,0 +1,261 @@
+package domain
+
+import (
+	"context"
+	"strings"
+
+	"github.com/gocql/gocql"
+)
+
+type KycStatus string
+
+const (
+	KycPending  KycStatus = "pending"
+	KycVerified KycStatus = "verified"
+	KycRejected KycStatus = "rejected"
+)
+
+func ParseKycStatus(value string) (KycStatus, error) {
+	switch status := KycStatus(value); status {
+	case KycPending, KycVerified, KycRejected:
+		return status, nil
+	default:
+		return "", NewDomainError("%s is not a valid KYC status, use %s, %s or %s", value, KycPending, KycVerified, KycRejected)
+	}
+}
+
+type Address struct {
+	Street     string
+	PostalCode string
+	City       string
+	Country    string
+}
+
+func (a Address) validate() error {
+	if strings.TrimSpace(a.Street) == "" || strings.TrimSpace(a.City) == "" || strings.TrimSpace(a.Country) == "" {
+		return NewDomainError("an address needs a street, a city and a country")
+	}
+	return nil
+}
+
+type Customer struct {
+	repo       CustomerEventRepository
+	customerId gocql.UUID
+	name       string
+	address    Address
+	kyc        KycStatus
+	accounts   []gocql.UUID
+}
+
+func (c *Customer) CustomerId() gocql.UUID {
+	return c.customerId
+}
+
+func (c *Customer) Name() string {
+	return c.name
+}
+
+func (c *Customer) Address() Address {
+	return c.address
+}
+
+func (c *Customer) KycStatus() KycStatus {
+	return c.kyc
+}
+
+func (c *Customer) Accounts() []gocql.UUID {
+	return append([]gocql.UUID{}, c.accounts...)
+}
+
+func (c *Customer) ChangeName(ctx context.Context, name string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return NewDomainError("the name of customer %s can not be empty", c.customerId)
+	}
+	if name == c.name {
+		return nil
+	}
+	return c.emit(ctx, CustomerNameChangedEvent{
+		CustomerId: c.customerId,
+		EventId:    gocql.TimeUUID(),
+		Name:       name,
+	})
+}
+
+func (c *Customer) ChangeAddress(ctx context.Context, address Address) error {
+	if err := address.validate(); err != nil {
+		return err
+	}
+	if address == c.address {
+		return nil
+	}
+	return c.emit(ctx, CustomerAddressChangedEvent{
+		CustomerId: c.customerId,
+		EventId:    gocql.TimeUUID(),
+		Address:    address,
+	})
+}
+
+func (c *Customer) ChangeKycStatus(ctx context.Context, status KycStatus, reason string) error {
+	if caller := CallerFrom(ctx); caller.IsCustomer() {
+		return NewPermissionDeniedError("%s can not change the KYC status of customer %s", caller.Name, c.customerId)
+	}
+	status, err := ParseKycStatus(string(status))
+	if err != nil {
+		return err
+	}
+	if status == c.kyc {
+		return NewDomainError("KYC status of customer %s is already %s", c.customerId, status)
+	}
+	return c.emit(ctx, CustomerKycStatusChangedEvent{
+		CustomerId: c.customerId,
+		EventId:    gocql.TimeUUID(),
+		Status:     status,
+		Reason:     reason,
+	})
+}
+
+func (c *Customer) LinkAccount(ctx context.Context, accountId gocql.UUID) error {
+	if c.owns(accountId) {
+		return NewDomainError("account %s is already linked to customer %s", accountId, c.customerId)
+	}
+	return c.emit(ctx, AccountLinkedEvent{
+		CustomerId: c.customerId,
+		EventId:    gocql.TimeUUID(),
+		AccountId:  accountId,
+	})
+}
+
+func (c *Customer) UnlinkAccount(ctx context.Context, accountId gocql.UUID) error {
+	if !c.owns(accountId) {
+		return NewDomainError("account %s is not linked to customer %s", accountId, c.customerId)
+	}
+	return c.emit(ctx, AccountUnlinkedEvent{
+		CustomerId: c.customerId,
+		EventId:    gocql.TimeUUID(),
+		AccountId:  accountId,
+	})
+}
+
+func (c *Customer) owns(accountId gocql.UUID) bool {
+	for _, id := range c.accounts {
+		if id == accountId {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Customer) emit(ctx context.Context, events ...CustomerEvent) error {
+	if err := c.repo.Write(ctx, events...); err != nil {
+		return err
+	}
+	for _, event := range events {
+		if err := event.Apply(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type CustomerService struct {
+	repo CustomerEventRepository
+}
+
+func NewCustomerService(repo CustomerEventRepository, timeouts OperationTimeouts) CustomerService {
+	return CustomerService{
+		repo: withCustomerTimeouts(repo, timeouts),
+	}
+}
+
+type NewCustomer struct {
+	Name    string
+	Address Address
+}
+
+func (s *CustomerService) CreateCustomer(ctx context.Context, newCustomer NewCustomer) (Customer, error) {
+	if caller := CallerFrom(ctx); caller.IsCustomer() {
+		return Customer{}, NewPermissionDeniedError("%s can not create customers", caller.Name)
+	}
+	name := strings.TrimSpace(newCustomer.Name)
+	if name == "" {
+		return Customer{}, NewDomainError("a customer needs a name")
+	}
+	if err := newCustomer.Address.validate(); err != nil {
+		return Customer{}, err
+	}
+	customer := Customer{repo: s.repo, customerId: gocql.MustRandomUUID()}
+	err := customer.emit(ctx, CustomerCreatedEvent{
+		CustomerId: customer.customerId,
+		EventId:    gocql.TimeUUID(),
+		Name:       name,
+		Address:    newCustomer.Address,
+	})
+	if err != nil {
+		return Customer{}, err
+	}
+	return customer, nil
+}
+
+func (s *CustomerService) GetCustomer(ctx context.Context, customerId gocql.UUID) (Customer, error) {
+	customer := Customer{repo: s.repo, customerId: customerId}
+	events, err := s.repo.ReadCustomerEvents(ctx, customerId)
+	if err != nil {
+		return Customer{}, err
+	}
+	if len(events) == 0 {
+		return Customer{}, NewCustomerNotFoundError("customer %s does not exist", customerId)
+	}
+	for _, event := range events {
+		if err := event.Apply(&customer); err != nil {
+			return Customer{}, err
+		}
+	}
+	return customer, nil
+}
+
+func (s *CustomerService) CustomerFor(ctx context.Context, customerId gocql.UUID) (Customer, error) {
+	if caller := CallerFrom(ctx); caller.IsCustomer() && caller.CustomerId != customerId {
+		return Customer{}, NewPermissionDeniedError("%s may not access customer %s", caller.Name, customerId)
+	}
+	return s.GetCustomer(ctx, customerId)
+}
+
+func (s *AccountService) GetCustomerAccounts(ctx context.Context, customerId gocql.UUID) ([]Account, error) {
+	if s.customers == nil {
+		return nil, NewDomainError("customers are not available")
+	}
+	customer, err := s.customers.CustomerFor(ctx, customerId)
+	if err != nil {
+		return nil, err
+	}
+	accounts := []Account{}
+	for _, accountId := range customer.accounts {
+		acc, err := s.GetAccount(ctx, accountId)
+		if err != nil {
+			if _, ok := err.(*AccountNotFoundError); ok {
+				continue
+			}
+			return accounts, err
+		}
+		accounts = append(accounts, acc)
+	}
+	return accounts, nil
+}
+
+func (s *AccountService) accountCustomer(ctx context.Context, customerId gocql.UUID) (Customer, error) {
+	if s.customers == nil {
+		return Customer{}, NewDomainError("customers are not available")
+	}
+	if customerId == (gocql.UUID{}) {
+		return Customer{}, NewDomainError("an account can only be opened for a customer")
+	}
+	customer, err := s.customers.GetCustomer(ctx, customerId)
+	if err != nil {
+		return Customer{}, err
+	}
+	if customer.kyc == KycRejected {
+		return Customer{}, NewDomainError("customer %s failed KYC and can not open accounts", customerId)
+	}
+	return customer, nil
+}
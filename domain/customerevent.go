@@ -0,0 +1,158 @@
+package domain
+
+import (
+	"fmt"
+
+	"github.com/gocql/gocql"
+)
+
+type CustomerEvent interface {
+	GetCustomerId() gocql.UUID
+	GetEventId() gocql.UUID
+	Apply(customer *Customer) error
+}
+
+type CustomerCreatedEvent struct {
+	CustomerId gocql.UUID
+	EventId    gocql.UUID
+	Name       string
+	Address    Address
+}
+
+func (e CustomerCreatedEvent) GetCustomerId() gocql.UUID {
+	return e.CustomerId
+}
+
+func (e CustomerCreatedEvent) GetEventId() gocql.UUID {
+	return e.EventId
+}
+
+func (e CustomerCreatedEvent) Apply(customer *Customer) error {
+	if e.CustomerId != customer.customerId {
+		return eventCustomerMismatched(e, customer)
+	}
+	customer.name = e.Name
+	customer.address = e.Address
+	customer.kyc = KycPending
+	return nil
+}
+
+type CustomerNameChangedEvent struct {
+	CustomerId gocql.UUID
+	EventId    gocql.UUID
+	Name       string
+}
+
+func (e CustomerNameChangedEvent) GetCustomerId() gocql.UUID {
+	return e.CustomerId
+}
+
+func (e CustomerNameChangedEvent) GetEventId() gocql.UUID {
+	return e.EventId
+}
+
+func (e CustomerNameChangedEvent) Apply(customer *Customer) error {
+	if e.CustomerId != customer.customerId {
+		return eventCustomerMismatched(e, customer)
+	}
+	customer.name = e.Name
+	return nil
+}
+
+type CustomerAddressChangedEvent struct {
+	CustomerId gocql.UUID
+	EventId    gocql.UUID
+	Address    Address
+}
+
+func (e CustomerAddressChangedEvent) GetCustomerId() gocql.UUID {
+	return e.CustomerId
+}
+
+func (e CustomerAddressChangedEvent) GetEventId() gocql.UUID {
+	return e.EventId
+}
+
+func (e CustomerAddressChangedEvent) Apply(customer *Customer) error {
+	if e.CustomerId != customer.customerId {
+		return eventCustomerMismatched(e, customer)
+	}
+	customer.address = e.Address
+	return nil
+}
+
+type CustomerKycStatusChangedEvent struct {
+	CustomerId gocql.UUID
+	EventId    gocql.UUID
+	Status     KycStatus
+	Reason     string
+}
+
+func (e CustomerKycStatusChangedEvent) GetCustomerId() gocql.UUID {
+	return e.CustomerId
+}
+
+func (e CustomerKycStatusChangedEvent) GetEventId() gocql.UUID {
+	return e.EventId
+}
+
+func (e CustomerKycStatusChangedEvent) Apply(customer *Customer) error {
+	if e.CustomerId != customer.customerId {
+		return eventCustomerMismatched(e, customer)
+	}
+	customer.kyc = e.Status
+	return nil
+}
+
+type AccountLinkedEvent struct {
+	CustomerId gocql.UUID
+	EventId    gocql.UUID
+	AccountId  gocql.UUID
+}
+
+func (e AccountLinkedEvent) GetCustomerId() gocql.UUID {
+	return e.CustomerId
+}
+
+func (e AccountLinkedEvent) GetEventId() gocql.UUID {
+	return e.EventId
+}
+
+func (e AccountLinkedEvent) Apply(customer *Customer) error {
+	if e.CustomerId != customer.customerId {
+		return eventCustomerMismatched(e, customer)
+	}
+	customer.accounts = append(customer.accounts, e.AccountId)
+	return nil
+}
+
+type AccountUnlinkedEvent struct {
+	CustomerId gocql.UUID
+	EventId    gocql.UUID
+	AccountId  gocql.UUID
+}
+
+func (e AccountUnlinkedEvent) GetCustomerId() gocql.UUID {
+	return e.CustomerId
+}
+
+func (e AccountUnlinkedEvent) GetEventId() gocql.UUID {
+	return e.EventId
+}
+
+func (e AccountUnlinkedEvent) Apply(customer *Customer) error {
+	if e.CustomerId != customer.customerId {
+		return eventCustomerMismatched(e, customer)
+	}
+	for i, id := range customer.accounts {
+		if id == e.AccountId {
+			customer.accounts = append(customer.accounts[:i], customer.accounts[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func eventCustomerMismatched(event CustomerEvent, customer *Customer) error {
+	return fmt.Errorf("event %+v is not an event of customer %s", event, customer.customerId)
+}
@@ -0,0 +1,28 @@
+package domain
+
+const (
+	maxReferenceLength   = 35
+	maxDescriptionLength = 140
+)
+
+type TransactionDetails struct {
+	Reference           string
+	Description         string
+	CounterpartyName    string
+	CounterpartyAccount string
+	Category            string
+}
+
+func (d TransactionDetails) IsZero() bool {
+	return d == TransactionDetails{}
+}
+
+func (d TransactionDetails) validate() error {
+	if len(d.Reference) > maxReferenceLength {
+		return NewDomainError("reference must not be longer than %d characters", maxReferenceLength)
+	}
+	if len(d.Description) > maxDescriptionLength {
+		return NewDomainError("description must not be longer than %d characters", maxDescriptionLength)
+	}
+	return nil
+}
@@ -0,0 +1,227 @@
+package domain
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gocql/gocql"
+)
+
+const DefaultDirectDebitRefundDays = 56
+
+type DirectDebitPolicy struct {
+	RefundDays int
+}
+
+type Mandate struct {
+	MandateId        gocql.UUID
+	CreditorId       string
+	CreditorName     string
+	Reference        string
+	MaxAmount        float64
+	MaxMonthlyAmount float64
+	SignedAt         time.Time
+}
+
+type DirectDebitCollection struct {
+	CollectionId gocql.UUID
+	MandateId    gocql.UUID
+	Amount       float64
+	CollectedAt  time.Time
+}
+
+func (m Mandate) validate(currency Currency) error {
+	if strings.TrimSpace(m.CreditorId) == "" || strings.TrimSpace(m.CreditorName) == "" {
+		return NewDomainError("a mandate needs a creditor id and a creditor name")
+	}
+	if strings.TrimSpace(m.Reference) == "" || len(m.Reference) > maxReferenceLength {
+		return NewDomainError("a mandate needs a reference of at most %d characters", maxReferenceLength)
+	}
+	if m.MaxAmount < 0 || m.MaxMonthlyAmount < 0 {
+		return NewDomainError("the amount limits of a mandate can not be negative")
+	}
+	if m.MaxAmount > 0 && m.MaxMonthlyAmount > 0 && m.MaxAmount > m.MaxMonthlyAmount {
+		return NewDomainError("the maximum amount %f of a mandate can not exceed its monthly maximum %f", m.MaxAmount, m.MaxMonthlyAmount)
+	}
+	if err := currency.checkAmount(m.MaxAmount); err != nil {
+		return err
+	}
+	return currency.checkAmount(m.MaxMonthlyAmount)
+}
+
+func (a *Account) Mandates() []Mandate {
+	mandates := make([]Mandate, 0, len(a.mandates))
+	for _, mandate := range a.mandates {
+		mandates = append(mandates, mandate)
+	}
+	sort.Slice(mandates, func(i, j int) bool {
+		return mandates[i].SignedAt.Before(mandates[j].SignedAt)
+	})
+	return mandates
+}
+
+func (a *Account) Mandate(mandateId gocql.UUID) (Mandate, error) {
+	mandate, ok := a.mandates[mandateId]
+	if !ok {
+		return mandate, NewDomainError("account %s has no active mandate %s", a.accountId, mandateId)
+	}
+	return mandate, nil
+}
+
+func (a *Account) Collection(collectionId gocql.UUID) (DirectDebitCollection, error) {
+	collection, ok := a.collections[collectionId]
+	if !ok {
+		return collection, NewDomainError("direct debit collection %s does not exist on account %s", collectionId, a.accountId)
+	}
+	return collection, nil
+}
+
+func (a *Account) CollectedThisMonth(mandateId gocql.UUID) float64 {
+	month := startOfMonth(a.clock.Now())
+	total := 0.0
+	for id, collection := range a.collections {
+		if collection.MandateId != mandateId || collection.CollectedAt.Before(month) {
+			continue
+		}
+		if _, reversed := a.ReversedBy(id); reversed {
+			continue
+		}
+		total += collection.Amount
+	}
+	return a.currency.round(total)
+}
+
+func (a *Account) CreateMandate(ctx context.Context, mandate Mandate) (Mandate, error) {
+	if err := a.requireState("create a mandate", AccountActive); err != nil {
+		return Mandate{}, err
+	}
+	if a.accountType.Profile().WithdrawalApproval {
+		return Mandate{}, NewDomainError("mandates can not be granted on %s accounts", a.accountType)
+	}
+	if err := mandate.validate(a.currency); err != nil {
+		return Mandate{}, err
+	}
+	for _, existing := range a.mandates {
+		if existing.CreditorId == mandate.CreditorId && existing.Reference == mandate.Reference {
+			return Mandate{}, NewDomainError("creditor %s already holds mandate %s on account %s", mandate.CreditorId, mandate.Reference, a.accountId)
+		}
+	}
+	e := MandateCreatedEvent{
+		AccountId:        a.accountId,
+		EventId:          gocql.TimeUUID(),
+		MandateId:        gocql.MustRandomUUID(),
+		CreditorId:       mandate.CreditorId,
+		CreditorName:     mandate.CreditorName,
+		Reference:        mandate.Reference,
+		MaxAmount:        mandate.MaxAmount,
+		MaxMonthlyAmount: mandate.MaxMonthlyAmount,
+	}
+	if err := a.emit(ctx, e); err != nil {
+		return Mandate{}, err
+	}
+	return a.mandates[e.MandateId], nil
+}
+
+func (a *Account) RevokeMandate(ctx context.Context, mandateId gocql.UUID, reason string) error {
+	if _, err := a.Mandate(mandateId); err != nil {
+		return err
+	}
+	return a.emit(ctx, MandateRevokedEvent{
+		AccountId: a.accountId,
+		EventId:   gocql.TimeUUID(),
+		MandateId: mandateId,
+		Reason:    reason,
+	})
+}
+
+func (a *Account) CollectDirectDebit(ctx context.Context, mandateId gocql.UUID, amount float64, currency Currency, details TransactionDetails) (DirectDebitCollection, error) {
+	if caller := CallerFrom(ctx); caller.IsCustomer() {
+		return DirectDebitCollection{}, NewPermissionDeniedError("%s can not collect direct debits", caller.Name)
+	}
+	mandate, err := a.Mandate(mandateId)
+	if err != nil {
+		return DirectDebitCollection{}, err
+	}
+	if amount <= 0 {
+		return DirectDebitCollection{}, NewDomainError("a collected amount %f must be positive", amount)
+	}
+	if mandate.MaxAmount > 0 && amount > mandate.MaxAmount {
+		return DirectDebitCollection{}, NewDomainError("the collected amount %f exceeds the maximum %f of mandate %s", amount, mandate.MaxAmount, mandate.Reference)
+	}
+	if a.accountType.Profile().WithdrawalApproval {
+		return DirectDebitCollection{}, NewDomainError("direct debits can not be collected from %s accounts", a.accountType)
+	}
+	if a.approval.WithdrawalThreshold > 0 && amount > a.approval.WithdrawalThreshold {
+		return DirectDebitCollection{}, NewDomainError("the collected amount %f exceeds the approval threshold %f", amount, a.approval.WithdrawalThreshold)
+	}
+	if collected := a.CollectedThisMonth(mandateId); mandate.MaxMonthlyAmount > 0 && collected+amount > mandate.MaxMonthlyAmount {
+		return DirectDebitCollection{}, NewDomainError("collecting %f would exceed the monthly maximum %f of mandate %s, %f were already collected", amount, mandate.MaxMonthlyAmount, mandate.Reference, collected)
+	}
+	if err := a.checkWithdrawal(amount); err != nil {
+		return DirectDebitCollection{}, err
+	}
+	if err := a.checkCurrency(amount, currency); err != nil {
+		return DirectDebitCollection{}, err
+	}
+	if details.Reference == "" {
+		details.Reference = mandate.Reference
+	}
+	if details.CounterpartyName == "" {
+		details.CounterpartyName = mandate.CreditorName
+	}
+	if err := details.validate(); err != nil {
+		return DirectDebitCollection{}, err
+	}
+	decision, err := a.checkRules(ctx, WithdrawCommand, amount)
+	if err != nil {
+		return DirectDebitCollection{}, err
+	}
+	e := DirectDebitCollectedEvent{
+		AccountId: a.accountId,
+		EventId:   gocql.TimeUUID(),
+		MandateId: mandateId,
+		Amount:    amount,
+		Currency:  a.currency,
+		Details:   details,
+	}
+	fees, _ := a.withdrawalFees(amount)
+	if err := a.emit(ctx, append(withDecision(decision, e), fees...)...); err != nil {
+		return DirectDebitCollection{}, err
+	}
+	return a.collections[e.EventId], nil
+}
+
+func (a *Account) refundDirectDebit(ctx context.Context, collectionId gocql.UUID, reason string, policy DirectDebitPolicy) error {
+	if err := a.requireState("refund a direct debit", AccountActive, AccountFrozen); err != nil {
+		return err
+	}
+	collection, err := a.Collection(collectionId)
+	if err != nil {
+		return err
+	}
+	if reversedBy, ok := a.ReversedBy(collectionId); ok {
+		return NewDomainError("direct debit collection %s was already reversed by %s", collectionId, reversedBy)
+	}
+	deadline := collection.CollectedAt.AddDate(0, 0, policy.RefundDays)
+	if a.clock.Now().After(deadline) {
+		return NewDomainError("direct debit collection %s could only be refunded until %s", collectionId, deadline.Format(time.DateOnly))
+	}
+	return a.emit(ctx, DirectDebitRefundedEvent{
+		AccountId:    a.accountId,
+		EventId:      gocql.TimeUUID(),
+		CollectionId: collectionId,
+		MandateId:    collection.MandateId,
+		Amount:       collection.Amount,
+		Reason:       reason,
+	})
+}
+
+func (s *AccountService) RefundDirectDebit(ctx context.Context, accountId, collectionId gocql.UUID, reason string) error {
+	acc, err := s.AccountFor(ctx, accountId, FullPermission)
+	if err != nil {
+		return err
+	}
+	return acc.refundDirectDebit(ctx, collectionId, reason, s.directDebit)
+}
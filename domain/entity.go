@@ -1,95 +1,289 @@
 package domain
 
 import (
+	"context"
+
 	"github.com/gocql/gocql"
 )
 
+type AccountState string
+
+const (
+	AccountPending AccountState = "pending"
+	AccountActive  AccountState = "active"
+	AccountFrozen  AccountState = "frozen"
+	AccountDeleted AccountState = "deleted"
+)
+
 type Account struct {
-	repo      AccountEventRepository
-	accountId gocql.UUID
-	deleted   bool
-	limit     float64
-	balance   float64
+	repo          AccountEventRepository
+	clock         Clock
+	rules         TransactionRules
+	approval      ApprovalPolicy
+	fees          FeeSchedules
+	accountId     gocql.UUID
+	accountType   AccountType
+	customerId    gocql.UUID
+	accountNumber AccountNumber
+	holders       map[gocql.UUID]Holder
+	state         AccountState
+	currency      Currency
+	limit         float64
+	balance       float64
+	holds         map[gocql.UUID]Hold
+	approvals     map[gocql.UUID]PendingApproval
+	policies      LimitPolicies
+
+	standingOrders     map[gocql.UUID]StandingOrder
+	mandates           map[gocql.UUID]Mandate
+	collections        map[gocql.UUID]DirectDebitCollection
+	transactions       map[gocql.UUID]bookedTransaction
+	transactionOrder   []gocql.UUID
+	reversals          map[gocql.UUID]gocql.UUID
+	recentTransactions []recentTransaction
+	monthlyWithdrawals monthlyWithdrawals
+
+	interest       interestState
+	balanceHistory []balancePoint
 }
 
 func (a *Account) Deleted() bool {
-	return a.deleted
+	return a.state == AccountDeleted
+}
+
+func (a *Account) State() AccountState {
+	return a.state
 }
 
 func (a *Account) AccountId() gocql.UUID {
 	return a.accountId
 }
 
+func (a *Account) CustomerId() gocql.UUID {
+	return a.customerId
+}
+
 func (a *Account) Balance() float64 {
 	return a.balance
 }
 
+func (a *Account) AvailableBalance() float64 {
+	available := a.balance
+	for _, hold := range a.holds {
+		available -= hold.Amount
+	}
+	return available
+}
+
+func (a *Account) Currency() Currency {
+	return a.currency
+}
+
 func (a *Account) Limit() float64 {
 	return a.limit
 }
 
-func (a *Account) SetNewLimit(limit float64) error {
-	if limit > 0 {
-		return NewDomainError("new limit %f can not be positive", limit)
+func (a *Account) SetNewLimit(ctx context.Context, limit float64) error {
+	if caller := CallerFrom(ctx); caller.IsCustomer() {
+		return NewPermissionDeniedError("%s can not set the limit of account %s", caller.Name, a.accountId)
 	}
-	if a.balance < limit {
-		return NewDomainError("new limit %f can not be set as balance %f would be below limit", limit, a.balance)
+	if err := a.checkLimit(limit); err != nil {
+		return err
+	}
+	if a.approval.LimitLowering && limit < a.limit {
+		return a.requestApproval(ctx, PendingApproval{Command: SetLimitApproval, Limit: limit}, nil)
 	}
-	e := LimitSetEvent{
+	return a.emit(ctx, LimitSetEvent{
 		AccountId: a.accountId,
 		EventId:   gocql.TimeUUID(),
 		Limit:     limit,
+	})
+}
+
+func (a *Account) Deposit(ctx context.Context, amount float64, currency Currency, details TransactionDetails) error {
+	if err := a.requireState("deposit", AccountActive, AccountFrozen); err != nil {
+		return err
+	}
+	if amount < 0 {
+		return NewDomainError("a negative amount %f can not be diposited", amount)
 	}
-	if err := a.repo.Write(e); err != nil {
+	if err := a.checkCurrency(amount, currency); err != nil {
 		return err
 	}
-	a.limit = limit
-	return nil
+	if err := details.validate(); err != nil {
+		return err
+	}
+	decision, err := a.checkRules(ctx, DepositCommand, amount)
+	if err != nil {
+		return err
+	}
+	return a.emit(ctx, withDecision(decision, MoneyDipositedEvent{
+		AccountId: a.accountId,
+		EventId:   gocql.TimeUUID(),
+		Amount:    amount,
+		Details:   details,
+		Currency:  a.currency,
+	})...)
 }
 
-func (a *Account) Deposit(amount float64) error {
-	if amount < 0 {
-		return NewDomainError("a negative amount %f can not be diposited", amount)
+func (a *Account) Withdraw(ctx context.Context, amount float64, currency Currency, details TransactionDetails) error {
+	if err := a.checkWithdrawal(amount); err != nil {
+		return err
 	}
-	e := MoneyDipositedEvent{
+	if err := a.checkCurrency(amount, currency); err != nil {
+		return err
+	}
+	if err := details.validate(); err != nil {
+		return err
+	}
+	decision, err := a.checkRules(ctx, WithdrawCommand, amount)
+	if err != nil {
+		return err
+	}
+	if a.accountType.Profile().WithdrawalApproval || a.approval.WithdrawalThreshold > 0 && amount > a.approval.WithdrawalThreshold {
+		return a.requestApproval(ctx, PendingApproval{Command: WithdrawApproval, Amount: amount, Details: details}, decision)
+	}
+	events := withDecision(decision, MoneyWithdrawnEvent{
 		AccountId: a.accountId,
 		EventId:   gocql.TimeUUID(),
 		Amount:    amount,
+		Details:   details,
+		Currency:  a.currency,
+	})
+	fees, _ := a.withdrawalFees(amount)
+	return a.emit(ctx, append(events, fees...)...)
+}
+
+func (a *Account) Close(ctx context.Context, payoutDestination string) error {
+	if err := a.requireState("close", AccountPending, AccountActive); err != nil {
+		return err
+	}
+	if len(a.holds) > 0 {
+		return NewDomainError("account %s can not be closed with %d open holds", a.accountId, len(a.holds))
+	}
+	if a.balance < 0 || (a.balance > 0 && payoutDestination == "") {
+		return NewBalanceRemainingError(a.balance, "account %s can not be closed with a remaining balance of %f", a.accountId, a.balance)
+	}
+	e := AccountClosedEvent{
+		AccountId:    a.accountId,
+		EventId:      gocql.TimeUUID(),
+		FinalBalance: a.balance,
+	}
+	if a.balance > 0 {
+		e.PayoutDestination = payoutDestination
+	}
+	return a.emit(ctx, e)
+}
+
+func (a *Account) Reopen(ctx context.Context) error {
+	if err := a.requireState("reopen", AccountDeleted); err != nil {
+		return err
+	}
+	return a.emit(ctx, AccountReopenedEvent{
+		AccountId: a.accountId,
+		EventId:   gocql.TimeUUID(),
+	})
+}
+
+func (a *Account) Activate(ctx context.Context) error {
+	if err := a.requireState("activate", AccountPending); err != nil {
+		return err
+	}
+	return a.emit(ctx, AccountActivatedEvent{
+		AccountId: a.accountId,
+		EventId:   gocql.TimeUUID(),
+	})
+}
+
+func (a *Account) Freeze(ctx context.Context, reason string) error {
+	if err := a.requireState("freeze", AccountActive); err != nil {
+		return err
 	}
-	if err := a.repo.Write(e); err != nil {
+	return a.emit(ctx, AccountFrozenEvent{
+		AccountId: a.accountId,
+		EventId:   gocql.TimeUUID(),
+		Reason:    reason,
+	})
+}
+
+func (a *Account) Unfreeze(ctx context.Context) error {
+	if err := a.requireState("unfreeze", AccountFrozen); err != nil {
 		return err
 	}
-	a.balance += amount
+	return a.emit(ctx, AccountUnfrozenEvent{
+		AccountId: a.accountId,
+		EventId:   gocql.TimeUUID(),
+	})
+}
+
+func (a *Account) checkLimit(limit float64) error {
+	if err := a.requireState("set a new limit", AccountActive); err != nil {
+		return err
+	}
+	if limit > 0 {
+		return NewDomainError("new limit %f can not be positive", limit)
+	}
+	if err := a.accountType.checkLimit(limit); err != nil {
+		return err
+	}
+	if err := a.currency.checkAmount(limit); err != nil {
+		return err
+	}
+	if a.AvailableBalance() < limit {
+		return NewDomainError("new limit %f can not be set as available balance %f would be below limit", limit, a.AvailableBalance())
+	}
 	return nil
 }
 
-func (a *Account) Withdraw(amount float64) error {
+func (a *Account) checkWithdrawal(amount float64) error {
+	if err := a.requireState("withdraw", AccountActive); err != nil {
+		return err
+	}
 	if amount < 0 {
 		return NewDomainError("a negative amount %f can not be withdrawn", amount)
 	}
-	if a.balance-amount < a.limit {
+	_, fees := a.withdrawalFees(amount)
+	if a.AvailableBalance()-amount-fees < a.limit {
 		return NewDomainError("the withdrawn amount %f would exceed the limit", amount)
 	}
-	e := MoneyWithdrawnEvent{
-		AccountId: a.accountId,
-		EventId:   gocql.TimeUUID(),
-		Amount:    amount,
+	if err := a.checkAccountType("withdraw"); err != nil {
+		return err
 	}
-	if err := a.repo.Write(e); err != nil {
+	return a.checkLimitPolicies(amount)
+}
+
+func (a *Account) requireState(action string, allowed ...AccountState) error {
+	for _, state := range allowed {
+		if a.state == state {
+			return nil
+		}
+	}
+	return NewDomainError("can not %s as account %s is %s", action, a.accountId, a.state)
+}
+
+func withDecision(decision *RuleDecisionRecordedEvent, events ...AccountEvent) []AccountEvent {
+	if decision == nil {
+		return events
+	}
+	return append([]AccountEvent{*decision}, events...)
+}
+
+func (a *Account) emit(ctx context.Context, events ...AccountEvent) error {
+	if err := a.repo.Write(ctx, events...); err != nil {
 		return err
 	}
-	a.balance -= amount
+	for _, event := range events {
+		if err := a.apply(event); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-func (a *Account) Delete() error {
-	e := AccountDeletedEvent{
-		AccountId: a.accountId,
-		EventId:   gocql.TimeUUID(),
-	}
-	if err := a.repo.Write(e); err != nil {
+func (a *Account) apply(event AccountEvent) error {
+	if err := event.Apply(a); err != nil {
 		return err
 	}
-	a.deleted = true
+	a.trackBalance(event.GetEventId().Time())
 	return nil
 }
@@ -25,3 +25,66 @@ func (e *AccountNotFoundError) Error() string {
 func NewAccountNotFoundError(format string, a ...any) *AccountNotFoundError {
 	return &AccountNotFoundError{Reason: fmt.Sprintf(format, a...)}
 }
+
+type CustomerNotFoundError struct {
+	Reason string
+}
+
+func (e *CustomerNotFoundError) Error() string {
+	return e.Reason
+}
+
+func NewCustomerNotFoundError(format string, a ...any) *CustomerNotFoundError {
+	return &CustomerNotFoundError{Reason: fmt.Sprintf(format, a...)}
+}
+
+type BalanceRemainingError struct {
+	Reason  string
+	Balance float64
+}
+
+func (e *BalanceRemainingError) Error() string {
+	return e.Reason
+}
+
+func NewBalanceRemainingError(balance float64, format string, a ...any) *BalanceRemainingError {
+	return &BalanceRemainingError{Reason: fmt.Sprintf(format, a...), Balance: balance}
+}
+
+type RuleDecisionError struct {
+	Reason  string
+	Outcome RuleOutcome
+}
+
+func (e *RuleDecisionError) Error() string {
+	return e.Reason
+}
+
+func NewRuleDecisionError(outcome RuleOutcome, format string, a ...any) *RuleDecisionError {
+	return &RuleDecisionError{Reason: fmt.Sprintf(format, a...), Outcome: outcome}
+}
+
+type PermissionDeniedError struct {
+	Reason string
+}
+
+func (e *PermissionDeniedError) Error() string {
+	return e.Reason
+}
+
+func NewPermissionDeniedError(format string, a ...any) *PermissionDeniedError {
+	return &PermissionDeniedError{Reason: fmt.Sprintf(format, a...)}
+}
+
+type ApprovalRequiredError struct {
+	Reason   string
+	Approval PendingApproval
+}
+
+func (e *ApprovalRequiredError) Error() string {
+	return e.Reason
+}
+
+func NewApprovalRequiredError(approval PendingApproval, format string, a ...any) *ApprovalRequiredError {
+	return &ApprovalRequiredError{Reason: fmt.Sprintf(format, a...), Approval: approval}
+}
@@ -2,6 +2,7 @@ package domain
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/gocql/gocql"
 )
@@ -13,8 +14,13 @@ type AccountEvent interface {
 }
 
 type AccountCreatedEvent struct {
-	AccountId gocql.UUID
-	EventId   gocql.UUID
+	AccountId     gocql.UUID
+	EventId       gocql.UUID
+	Pending       bool
+	Currency      Currency
+	Type          AccountType
+	CustomerId    gocql.UUID
+	AccountNumber AccountNumber
 }
 
 func (e AccountCreatedEvent) GetAccountId() gocql.UUID {
@@ -29,6 +35,25 @@ func (e AccountCreatedEvent) Apply(account *Account) error {
 	if e.AccountId != account.accountId {
 		return eventAccountMismatched(e, account)
 	}
+	account.state = AccountActive
+	if e.Pending {
+		account.state = AccountPending
+	}
+	account.currency = e.Currency
+	if account.currency == "" {
+		account.currency = DefaultCurrency
+	}
+	account.accountType = e.Type
+	if account.accountType == "" {
+		account.accountType = CheckingAccount
+	}
+	account.customerId = e.CustomerId
+	account.accountNumber = e.AccountNumber
+	if e.CustomerId != (gocql.UUID{}) {
+		account.holders = map[gocql.UUID]Holder{
+			e.CustomerId: {CustomerId: e.CustomerId, Permission: FullPermission},
+		}
+	}
 	return nil
 }
 
@@ -49,7 +74,116 @@ func (e AccountDeletedEvent) Apply(account *Account) error {
 	if e.AccountId != account.accountId {
 		return eventAccountMismatched(e, account)
 	}
-	account.deleted = true
+	account.state = AccountDeleted
+	return nil
+}
+
+type AccountClosedEvent struct {
+	AccountId         gocql.UUID
+	EventId           gocql.UUID
+	FinalBalance      float64
+	PayoutDestination string
+}
+
+func (e AccountClosedEvent) GetAccountId() gocql.UUID {
+	return e.AccountId
+}
+
+func (e AccountClosedEvent) GetEventId() gocql.UUID {
+	return e.EventId
+}
+
+func (e AccountClosedEvent) Apply(account *Account) error {
+	if e.AccountId != account.accountId {
+		return eventAccountMismatched(e, account)
+	}
+	account.balance -= e.FinalBalance
+	account.state = AccountDeleted
+	return nil
+}
+
+type AccountReopenedEvent struct {
+	AccountId gocql.UUID
+	EventId   gocql.UUID
+}
+
+func (e AccountReopenedEvent) GetAccountId() gocql.UUID {
+	return e.AccountId
+}
+
+func (e AccountReopenedEvent) GetEventId() gocql.UUID {
+	return e.EventId
+}
+
+func (e AccountReopenedEvent) Apply(account *Account) error {
+	if e.AccountId != account.accountId {
+		return eventAccountMismatched(e, account)
+	}
+	account.state = AccountActive
+	return nil
+}
+
+type AccountActivatedEvent struct {
+	AccountId gocql.UUID
+	EventId   gocql.UUID
+}
+
+func (e AccountActivatedEvent) GetAccountId() gocql.UUID {
+	return e.AccountId
+}
+
+func (e AccountActivatedEvent) GetEventId() gocql.UUID {
+	return e.EventId
+}
+
+func (e AccountActivatedEvent) Apply(account *Account) error {
+	if e.AccountId != account.accountId {
+		return eventAccountMismatched(e, account)
+	}
+	account.state = AccountActive
+	return nil
+}
+
+type AccountFrozenEvent struct {
+	AccountId gocql.UUID
+	EventId   gocql.UUID
+	Reason    string
+}
+
+func (e AccountFrozenEvent) GetAccountId() gocql.UUID {
+	return e.AccountId
+}
+
+func (e AccountFrozenEvent) GetEventId() gocql.UUID {
+	return e.EventId
+}
+
+func (e AccountFrozenEvent) Apply(account *Account) error {
+	if e.AccountId != account.accountId {
+		return eventAccountMismatched(e, account)
+	}
+	account.state = AccountFrozen
+	return nil
+}
+
+type AccountUnfrozenEvent struct {
+	AccountId gocql.UUID
+	EventId   gocql.UUID
+}
+
+func (e AccountUnfrozenEvent) GetAccountId() gocql.UUID {
+	return e.AccountId
+}
+
+func (e AccountUnfrozenEvent) GetEventId() gocql.UUID {
+	return e.EventId
+}
+
+func (e AccountUnfrozenEvent) Apply(account *Account) error {
+	if e.AccountId != account.accountId {
+		return eventAccountMismatched(e, account)
+	}
+	account.state = AccountActive
 	return nil
 }
 
@@ -57,6 +191,8 @@ type MoneyDipositedEvent struct {
 	AccountId gocql.UUID
 	EventId   gocql.UUID
 	Amount    float64
+	Currency  Currency
+	Details   TransactionDetails
 }
 
 func (e MoneyDipositedEvent) GetAccountId() gocql.UUID {
@@ -71,7 +207,11 @@ func (e MoneyDipositedEvent) Apply(account *Account) error {
 	if e.AccountId != account.accountId {
 		return eventAccountMismatched(e, account)
 	}
+	if e.Currency != "" && e.Currency != account.currency {
+		return fmt.Errorf("event %s in %s does not match the currency %s of account %s", e.EventId, e.Currency, account.currency, account.accountId)
+	}
 	account.balance += e.Amount
+	account.bookTransaction(e.EventId, DepositCommand, e.Amount)
 	return nil
 }
 
@@ -79,6 +219,8 @@ type MoneyWithdrawnEvent struct {
 	AccountId gocql.UUID
 	EventId   gocql.UUID
 	Amount    float64
+	Currency  Currency
+	Details   TransactionDetails
 }
 
 func (e MoneyWithdrawnEvent) GetAccountId() gocql.UUID {
@@ -93,7 +235,11 @@ func (e MoneyWithdrawnEvent) Apply(account *Account) error {
 	if e.AccountId != account.accountId {
 		return eventAccountMismatched(e, account)
 	}
+	if e.Currency != "" && e.Currency != account.currency {
+		return fmt.Errorf("event %s in %s does not match the currency %s of account %s", e.EventId, e.Currency, account.currency, account.accountId)
+	}
 	account.balance -= e.Amount
+	account.bookTransaction(e.EventId, WithdrawCommand, e.Amount)
 	return nil
 }
 
@@ -119,6 +265,845 @@ func (e LimitSetEvent) Apply(account *Account) error {
 	return nil
 }
 
+type LimitPoliciesSetEvent struct {
+	AccountId gocql.UUID
+	EventId   gocql.UUID
+	Policies  LimitPolicies
+}
+
+func (e LimitPoliciesSetEvent) GetAccountId() gocql.UUID {
+	return e.AccountId
+}
+
+func (e LimitPoliciesSetEvent) GetEventId() gocql.UUID {
+	return e.EventId
+}
+
+func (e LimitPoliciesSetEvent) Apply(account *Account) error {
+	if e.AccountId != account.accountId {
+		return eventAccountMismatched(e, account)
+	}
+	account.policies = e.Policies
+	return nil
+}
+
+type HoldPlacedEvent struct {
+	AccountId gocql.UUID
+	EventId   gocql.UUID
+	HoldId    gocql.UUID
+	Amount    float64
+	ExpiresAt time.Time
+}
+
+func (e HoldPlacedEvent) GetAccountId() gocql.UUID {
+	return e.AccountId
+}
+
+func (e HoldPlacedEvent) GetEventId() gocql.UUID {
+	return e.EventId
+}
+
+func (e HoldPlacedEvent) Apply(account *Account) error {
+	if e.AccountId != account.accountId {
+		return eventAccountMismatched(e, account)
+	}
+	if account.holds == nil {
+		account.holds = map[gocql.UUID]Hold{}
+	}
+	account.holds[e.HoldId] = Hold{
+		HoldId:    e.HoldId,
+		Amount:    e.Amount,
+		ExpiresAt: e.ExpiresAt,
+	}
+	return nil
+}
+
+type HoldCapturedEvent struct {
+	AccountId gocql.UUID
+	EventId   gocql.UUID
+	HoldId    gocql.UUID
+	Amount    float64
+}
+
+func (e HoldCapturedEvent) GetAccountId() gocql.UUID {
+	return e.AccountId
+}
+
+func (e HoldCapturedEvent) GetEventId() gocql.UUID {
+	return e.EventId
+}
+
+func (e HoldCapturedEvent) Apply(account *Account) error {
+	if e.AccountId != account.accountId {
+		return eventAccountMismatched(e, account)
+	}
+	account.balance -= e.Amount
+	account.bookSettledTransaction(e.EventId, WithdrawCommand, e.Amount)
+	delete(account.holds, e.HoldId)
+	return nil
+}
+
+type HoldReleasedEvent struct {
+	AccountId gocql.UUID
+	EventId   gocql.UUID
+	HoldId    gocql.UUID
+}
+
+func (e HoldReleasedEvent) GetAccountId() gocql.UUID {
+	return e.AccountId
+}
+
+func (e HoldReleasedEvent) GetEventId() gocql.UUID {
+	return e.EventId
+}
+
+func (e HoldReleasedEvent) Apply(account *Account) error {
+	if e.AccountId != account.accountId {
+		return eventAccountMismatched(e, account)
+	}
+	delete(account.holds, e.HoldId)
+	return nil
+}
+
+type HoldExpiredEvent struct {
+	AccountId gocql.UUID
+	EventId   gocql.UUID
+	HoldId    gocql.UUID
+}
+
+func (e HoldExpiredEvent) GetAccountId() gocql.UUID {
+	return e.AccountId
+}
+
+func (e HoldExpiredEvent) GetEventId() gocql.UUID {
+	return e.EventId
+}
+
+func (e HoldExpiredEvent) Apply(account *Account) error {
+	if e.AccountId != account.accountId {
+		return eventAccountMismatched(e, account)
+	}
+	delete(account.holds, e.HoldId)
+	return nil
+}
+
+type RuleDecisionRecordedEvent struct {
+	AccountId   gocql.UUID
+	EventId     gocql.UUID
+	Command     TransactionCommand
+	Amount      float64
+	Outcome     RuleOutcome
+	Rule        string
+	RuleVersion int
+}
+
+func (e RuleDecisionRecordedEvent) GetAccountId() gocql.UUID {
+	return e.AccountId
+}
+
+func (e RuleDecisionRecordedEvent) GetEventId() gocql.UUID {
+	return e.EventId
+}
+
+func (e RuleDecisionRecordedEvent) Apply(account *Account) error {
+	if e.AccountId != account.accountId {
+		return eventAccountMismatched(e, account)
+	}
+	return nil
+}
+
 func eventAccountMismatched(event AccountEvent, account *Account) error {
 	return fmt.Errorf("event %+v is not an event of account %s", event, account.accountId)
 }
+
+type ApprovalRequestedEvent struct {
+	AccountId   gocql.UUID
+	EventId     gocql.UUID
+	RequestId   gocql.UUID
+	Command     ApprovalCommand
+	Amount      float64
+	Limit       float64
+	Details     TransactionDetails
+	RequestedBy string
+	ExpiresAt   time.Time
+}
+
+func (e ApprovalRequestedEvent) GetAccountId() gocql.UUID {
+	return e.AccountId
+}
+
+func (e ApprovalRequestedEvent) GetEventId() gocql.UUID {
+	return e.EventId
+}
+
+func (e ApprovalRequestedEvent) Apply(account *Account) error {
+	if e.AccountId != account.accountId {
+		return eventAccountMismatched(e, account)
+	}
+	if account.approvals == nil {
+		account.approvals = map[gocql.UUID]PendingApproval{}
+	}
+	account.approvals[e.RequestId] = PendingApproval{
+		RequestId:   e.RequestId,
+		Command:     e.Command,
+		Amount:      e.Amount,
+		Limit:       e.Limit,
+		Details:     e.Details,
+		RequestedBy: e.RequestedBy,
+		ExpiresAt:   e.ExpiresAt,
+	}
+	return nil
+}
+
+type ApprovalGrantedEvent struct {
+	AccountId  gocql.UUID
+	EventId    gocql.UUID
+	RequestId  gocql.UUID
+	ApprovedBy string
+}
+
+func (e ApprovalGrantedEvent) GetAccountId() gocql.UUID {
+	return e.AccountId
+}
+
+func (e ApprovalGrantedEvent) GetEventId() gocql.UUID {
+	return e.EventId
+}
+
+func (e ApprovalGrantedEvent) Apply(account *Account) error {
+	if e.AccountId != account.accountId {
+		return eventAccountMismatched(e, account)
+	}
+	delete(account.approvals, e.RequestId)
+	return nil
+}
+
+type ApprovalRejectedEvent struct {
+	AccountId  gocql.UUID
+	EventId    gocql.UUID
+	RequestId  gocql.UUID
+	RejectedBy string
+	Reason     string
+}
+
+func (e ApprovalRejectedEvent) GetAccountId() gocql.UUID {
+	return e.AccountId
+}
+
+func (e ApprovalRejectedEvent) GetEventId() gocql.UUID {
+	return e.EventId
+}
+
+func (e ApprovalRejectedEvent) Apply(account *Account) error {
+	if e.AccountId != account.accountId {
+		return eventAccountMismatched(e, account)
+	}
+	delete(account.approvals, e.RequestId)
+	return nil
+}
+
+type ApprovalExpiredEvent struct {
+	AccountId gocql.UUID
+	EventId   gocql.UUID
+	RequestId gocql.UUID
+}
+
+func (e ApprovalExpiredEvent) GetAccountId() gocql.UUID {
+	return e.AccountId
+}
+
+func (e ApprovalExpiredEvent) GetEventId() gocql.UUID {
+	return e.EventId
+}
+
+func (e ApprovalExpiredEvent) Apply(account *Account) error {
+	if e.AccountId != account.accountId {
+		return eventAccountMismatched(e, account)
+	}
+	delete(account.approvals, e.RequestId)
+	return nil
+}
+
+type TransactionReversedEvent struct {
+	AccountId       gocql.UUID
+	EventId         gocql.UUID
+	ReversedEventId gocql.UUID
+	Command         TransactionCommand
+	Amount          float64
+	Reason          string
+}
+
+func (e TransactionReversedEvent) GetAccountId() gocql.UUID {
+	return e.AccountId
+}
+
+func (e TransactionReversedEvent) GetEventId() gocql.UUID {
+	return e.EventId
+}
+
+func (e TransactionReversedEvent) Apply(account *Account) error {
+	if e.AccountId != account.accountId {
+		return eventAccountMismatched(e, account)
+	}
+	if _, ok := account.reversals[e.ReversedEventId]; ok {
+		return fmt.Errorf("reversed event %s of account %s was already reversed", e.ReversedEventId, account.accountId)
+	}
+	switch e.Command {
+	case DepositCommand:
+		account.balance -= e.Amount
+	case WithdrawCommand:
+		account.balance += e.Amount
+	default:
+		return fmt.Errorf("reversed command %s is not known", e.Command)
+	}
+	account.reverse(e.ReversedEventId, e.EventId)
+	return nil
+}
+
+type ExchangeDebitedEvent struct {
+	AccountId        gocql.UUID
+	EventId          gocql.UUID
+	ExchangeId       gocql.UUID
+	Amount           float64
+	Currency         Currency
+	CounterAccountId gocql.UUID
+	CounterAmount    float64
+	CounterCurrency  Currency
+	Rate             float64
+	Spread           float64
+	SnapshotId       gocql.UUID
+}
+
+func (e ExchangeDebitedEvent) GetAccountId() gocql.UUID {
+	return e.AccountId
+}
+
+func (e ExchangeDebitedEvent) GetEventId() gocql.UUID {
+	return e.EventId
+}
+
+func (e ExchangeDebitedEvent) Apply(account *Account) error {
+	if e.AccountId != account.accountId {
+		return eventAccountMismatched(e, account)
+	}
+	if e.Currency != account.currency {
+		return fmt.Errorf("event %s in %s does not match the currency %s of account %s", e.EventId, e.Currency, account.currency, account.accountId)
+	}
+	account.balance -= e.Amount
+	account.bookSettledTransaction(e.EventId, WithdrawCommand, e.Amount)
+	return nil
+}
+
+type ExchangeCreditedEvent struct {
+	AccountId        gocql.UUID
+	EventId          gocql.UUID
+	ExchangeId       gocql.UUID
+	Amount           float64
+	Currency         Currency
+	CounterAccountId gocql.UUID
+	CounterAmount    float64
+	CounterCurrency  Currency
+	Rate             float64
+	Spread           float64
+	SnapshotId       gocql.UUID
+}
+
+func (e ExchangeCreditedEvent) GetAccountId() gocql.UUID {
+	return e.AccountId
+}
+
+func (e ExchangeCreditedEvent) GetEventId() gocql.UUID {
+	return e.EventId
+}
+
+func (e ExchangeCreditedEvent) Apply(account *Account) error {
+	if e.AccountId != account.accountId {
+		return eventAccountMismatched(e, account)
+	}
+	if e.Currency != account.currency {
+		return fmt.Errorf("event %s in %s does not match the currency %s of account %s", e.EventId, e.Currency, account.currency, account.accountId)
+	}
+	account.balance += e.Amount
+	account.bookSettledTransaction(e.EventId, DepositCommand, e.Amount)
+	return nil
+}
+
+type InterestRateSetEvent struct {
+	AccountId gocql.UUID
+	EventId   gocql.UUID
+	Rate      float64
+	DayCount  DayCountConvention
+}
+
+func (e InterestRateSetEvent) GetAccountId() gocql.UUID {
+	return e.AccountId
+}
+
+func (e InterestRateSetEvent) GetEventId() gocql.UUID {
+	return e.EventId
+}
+
+func (e InterestRateSetEvent) Apply(account *Account) error {
+	if e.AccountId != account.accountId {
+		return eventAccountMismatched(e, account)
+	}
+	account.interest.terms = InterestTerms{Rate: e.Rate, DayCount: e.DayCount}
+	if account.interest.accruedThrough.IsZero() {
+		account.interest.accruedThrough = startOfDay(e.EventId.Time()).Add(-day)
+	}
+	return nil
+}
+
+type InterestAccruedEvent struct {
+	AccountId   gocql.UUID
+	EventId     gocql.UUID
+	From        time.Time
+	Through     time.Time
+	Amount      float64
+	Rate        float64
+	DayCount    DayCountConvention
+	DebitAmount float64
+	DebitRate   float64
+}
+
+func (e InterestAccruedEvent) GetAccountId() gocql.UUID {
+	return e.AccountId
+}
+
+func (e InterestAccruedEvent) GetEventId() gocql.UUID {
+	return e.EventId
+}
+
+func (e InterestAccruedEvent) Apply(account *Account) error {
+	if e.AccountId != account.accountId {
+		return eventAccountMismatched(e, account)
+	}
+	account.interest.accrued += e.Amount
+	account.interest.debitAccrued += e.DebitAmount
+	account.interest.accruedThrough = e.Through
+	account.pruneBalanceHistory()
+	return nil
+}
+
+type InterestCreditedEvent struct {
+	AccountId gocql.UUID
+	EventId   gocql.UUID
+	Amount    float64
+	Month     string
+}
+
+func (e InterestCreditedEvent) GetAccountId() gocql.UUID {
+	return e.AccountId
+}
+
+func (e InterestCreditedEvent) GetEventId() gocql.UUID {
+	return e.EventId
+}
+
+func (e InterestCreditedEvent) Apply(account *Account) error {
+	if e.AccountId != account.accountId {
+		return eventAccountMismatched(e, account)
+	}
+	account.balance += e.Amount
+	account.interest.accrued -= e.Amount
+	return nil
+}
+
+type FeeChargedEvent struct {
+	AccountId gocql.UUID
+	EventId   gocql.UUID
+	Kind      FeeKind
+	Amount    float64
+	Currency  Currency
+	Month     string
+}
+
+func (e FeeChargedEvent) GetAccountId() gocql.UUID {
+	return e.AccountId
+}
+
+func (e FeeChargedEvent) GetEventId() gocql.UUID {
+	return e.EventId
+}
+
+func (e FeeChargedEvent) Apply(account *Account) error {
+	if e.AccountId != account.accountId {
+		return eventAccountMismatched(e, account)
+	}
+	account.balance -= e.Amount
+	if e.Kind == OverdraftInterestFee {
+		account.interest.debitAccrued -= e.Amount
+	}
+	return nil
+}
+
+type HolderAddedEvent struct {
+	AccountId       gocql.UUID
+	EventId         gocql.UUID
+	CustomerId      gocql.UUID
+	Permission      HolderPermission
+	WithdrawalLimit float64
+}
+
+func (e HolderAddedEvent) GetAccountId() gocql.UUID {
+	return e.AccountId
+}
+
+func (e HolderAddedEvent) GetEventId() gocql.UUID {
+	return e.EventId
+}
+
+func (e HolderAddedEvent) Apply(account *Account) error {
+	if e.AccountId != account.accountId {
+		return eventAccountMismatched(e, account)
+	}
+	if account.holders == nil {
+		account.holders = map[gocql.UUID]Holder{}
+	}
+	account.holders[e.CustomerId] = Holder{CustomerId: e.CustomerId, Permission: e.Permission, WithdrawalLimit: e.WithdrawalLimit}
+	return nil
+}
+
+type HolderPermissionChangedEvent struct {
+	AccountId       gocql.UUID
+	EventId         gocql.UUID
+	CustomerId      gocql.UUID
+	Permission      HolderPermission
+	WithdrawalLimit float64
+}
+
+func (e HolderPermissionChangedEvent) GetAccountId() gocql.UUID {
+	return e.AccountId
+}
+
+func (e HolderPermissionChangedEvent) GetEventId() gocql.UUID {
+	return e.EventId
+}
+
+func (e HolderPermissionChangedEvent) Apply(account *Account) error {
+	if e.AccountId != account.accountId {
+		return eventAccountMismatched(e, account)
+	}
+	if _, ok := account.holders[e.CustomerId]; !ok {
+		return fmt.Errorf("customer %s of event %s does not hold account %s", e.CustomerId, e.EventId, account.accountId)
+	}
+	account.holders[e.CustomerId] = Holder{CustomerId: e.CustomerId, Permission: e.Permission, WithdrawalLimit: e.WithdrawalLimit}
+	return nil
+}
+
+type HolderRemovedEvent struct {
+	AccountId  gocql.UUID
+	EventId    gocql.UUID
+	CustomerId gocql.UUID
+}
+
+func (e HolderRemovedEvent) GetAccountId() gocql.UUID {
+	return e.AccountId
+}
+
+func (e HolderRemovedEvent) GetEventId() gocql.UUID {
+	return e.EventId
+}
+
+func (e HolderRemovedEvent) Apply(account *Account) error {
+	if e.AccountId != account.accountId {
+		return eventAccountMismatched(e, account)
+	}
+	delete(account.holders, e.CustomerId)
+	return nil
+}
+
+type StandingOrderCreatedEvent struct {
+	AccountId   gocql.UUID
+	EventId     gocql.UUID
+	OrderId     gocql.UUID
+	ToAccountId gocql.UUID
+	Amount      float64
+	Interval    StandingOrderInterval
+	StartDate   time.Time
+	EndDate     time.Time
+	Details     TransactionDetails
+}
+
+func (e StandingOrderCreatedEvent) GetAccountId() gocql.UUID {
+	return e.AccountId
+}
+
+func (e StandingOrderCreatedEvent) GetEventId() gocql.UUID {
+	return e.EventId
+}
+
+func (e StandingOrderCreatedEvent) Apply(account *Account) error {
+	if e.AccountId != account.accountId {
+		return eventAccountMismatched(e, account)
+	}
+	if account.standingOrders == nil {
+		account.standingOrders = map[gocql.UUID]StandingOrder{}
+	}
+	account.standingOrders[e.OrderId] = StandingOrder{
+		OrderId:       e.OrderId,
+		ToAccountId:   e.ToAccountId,
+		Amount:        e.Amount,
+		Interval:      e.Interval,
+		StartDate:     e.StartDate,
+		EndDate:       e.EndDate,
+		Details:       e.Details,
+		NextExecution: e.StartDate,
+	}
+	return nil
+}
+
+type StandingOrderAmendedEvent struct {
+	AccountId     gocql.UUID
+	EventId       gocql.UUID
+	OrderId       gocql.UUID
+	Amount        float64
+	Interval      StandingOrderInterval
+	NextExecution time.Time
+	EndDate       time.Time
+	Details       TransactionDetails
+}
+
+func (e StandingOrderAmendedEvent) GetAccountId() gocql.UUID {
+	return e.AccountId
+}
+
+func (e StandingOrderAmendedEvent) GetEventId() gocql.UUID {
+	return e.EventId
+}
+
+func (e StandingOrderAmendedEvent) Apply(account *Account) error {
+	if e.AccountId != account.accountId {
+		return eventAccountMismatched(e, account)
+	}
+	order, ok := account.standingOrders[e.OrderId]
+	if !ok {
+		return fmt.Errorf("standing order %s of event %s does not exist on account %s", e.OrderId, e.EventId, account.accountId)
+	}
+	if !e.NextExecution.Equal(order.NextExecution) {
+		order.StartDate = e.NextExecution
+	}
+	order.Amount = e.Amount
+	order.Interval = e.Interval
+	order.NextExecution = e.NextExecution
+	order.EndDate = e.EndDate
+	order.Details = e.Details
+	order.Attempts = 0
+	order.RetryAt = time.Time{}
+	account.standingOrders[e.OrderId] = order
+	return nil
+}
+
+type StandingOrderCancelledEvent struct {
+	AccountId gocql.UUID
+	EventId   gocql.UUID
+	OrderId   gocql.UUID
+	Reason    string
+}
+
+func (e StandingOrderCancelledEvent) GetAccountId() gocql.UUID {
+	return e.AccountId
+}
+
+func (e StandingOrderCancelledEvent) GetEventId() gocql.UUID {
+	return e.EventId
+}
+
+func (e StandingOrderCancelledEvent) Apply(account *Account) error {
+	if e.AccountId != account.accountId {
+		return eventAccountMismatched(e, account)
+	}
+	delete(account.standingOrders, e.OrderId)
+	return nil
+}
+
+type StandingOrderExecutedEvent struct {
+	AccountId     gocql.UUID
+	EventId       gocql.UUID
+	OrderId       gocql.UUID
+	DueDate       time.Time
+	Amount        float64
+	TransactionId gocql.UUID
+	NextExecution time.Time
+}
+
+func (e StandingOrderExecutedEvent) GetAccountId() gocql.UUID {
+	return e.AccountId
+}
+
+func (e StandingOrderExecutedEvent) GetEventId() gocql.UUID {
+	return e.EventId
+}
+
+func (e StandingOrderExecutedEvent) Apply(account *Account) error {
+	if e.AccountId != account.accountId {
+		return eventAccountMismatched(e, account)
+	}
+	account.scheduleStandingOrder(e.OrderId, e.NextExecution)
+	return nil
+}
+
+type StandingOrderFailedEvent struct {
+	AccountId     gocql.UUID
+	EventId       gocql.UUID
+	OrderId       gocql.UUID
+	DueDate       time.Time
+	Attempt       int
+	Reason        string
+	RetryAt       time.Time
+	NextExecution time.Time
+}
+
+func (e StandingOrderFailedEvent) GetAccountId() gocql.UUID {
+	return e.AccountId
+}
+
+func (e StandingOrderFailedEvent) GetEventId() gocql.UUID {
+	return e.EventId
+}
+
+func (e StandingOrderFailedEvent) Apply(account *Account) error {
+	if e.AccountId != account.accountId {
+		return eventAccountMismatched(e, account)
+	}
+	order, ok := account.standingOrders[e.OrderId]
+	if !ok {
+		return nil
+	}
+	if e.RetryAt.IsZero() {
+		account.scheduleStandingOrder(e.OrderId, e.NextExecution)
+		return nil
+	}
+	order.Attempts = e.Attempt
+	order.RetryAt = e.RetryAt
+	account.standingOrders[e.OrderId] = order
+	return nil
+}
+
+type MandateCreatedEvent struct {
+	AccountId        gocql.UUID
+	EventId          gocql.UUID
+	MandateId        gocql.UUID
+	CreditorId       string
+	CreditorName     string
+	Reference        string
+	MaxAmount        float64
+	MaxMonthlyAmount float64
+}
+
+func (e MandateCreatedEvent) GetAccountId() gocql.UUID {
+	return e.AccountId
+}
+
+func (e MandateCreatedEvent) GetEventId() gocql.UUID {
+	return e.EventId
+}
+
+func (e MandateCreatedEvent) Apply(account *Account) error {
+	if e.AccountId != account.accountId {
+		return eventAccountMismatched(e, account)
+	}
+	if account.mandates == nil {
+		account.mandates = map[gocql.UUID]Mandate{}
+	}
+	account.mandates[e.MandateId] = Mandate{
+		MandateId:        e.MandateId,
+		CreditorId:       e.CreditorId,
+		CreditorName:     e.CreditorName,
+		Reference:        e.Reference,
+		MaxAmount:        e.MaxAmount,
+		MaxMonthlyAmount: e.MaxMonthlyAmount,
+		SignedAt:         e.EventId.Time(),
+	}
+	return nil
+}
+
+type MandateRevokedEvent struct {
+	AccountId gocql.UUID
+	EventId   gocql.UUID
+	MandateId gocql.UUID
+	Reason    string
+}
+
+func (e MandateRevokedEvent) GetAccountId() gocql.UUID {
+	return e.AccountId
+}
+
+func (e MandateRevokedEvent) GetEventId() gocql.UUID {
+	return e.EventId
+}
+
+func (e MandateRevokedEvent) Apply(account *Account) error {
+	if e.AccountId != account.accountId {
+		return eventAccountMismatched(e, account)
+	}
+	delete(account.mandates, e.MandateId)
+	return nil
+}
+
+type DirectDebitCollectedEvent struct {
+	AccountId gocql.UUID
+	EventId   gocql.UUID
+	MandateId gocql.UUID
+	Amount    float64
+	Currency  Currency
+	Details   TransactionDetails
+}
+
+func (e DirectDebitCollectedEvent) GetAccountId() gocql.UUID {
+	return e.AccountId
+}
+
+func (e DirectDebitCollectedEvent) GetEventId() gocql.UUID {
+	return e.EventId
+}
+
+func (e DirectDebitCollectedEvent) Apply(account *Account) error {
+	if e.AccountId != account.accountId {
+		return eventAccountMismatched(e, account)
+	}
+	if e.Currency != account.currency {
+		return fmt.Errorf("event %s in %s does not match the currency %s of account %s", e.EventId, e.Currency, account.currency, account.accountId)
+	}
+	account.balance -= e.Amount
+	account.bookSettledTransaction(e.EventId, WithdrawCommand, e.Amount)
+	if account.collections == nil {
+		account.collections = map[gocql.UUID]DirectDebitCollection{}
+	}
+	account.collections[e.EventId] = DirectDebitCollection{
+		CollectionId: e.EventId,
+		MandateId:    e.MandateId,
+		Amount:       e.Amount,
+		CollectedAt:  e.EventId.Time(),
+	}
+	return nil
+}
+
+type DirectDebitRefundedEvent struct {
+	AccountId    gocql.UUID
+	EventId      gocql.UUID
+	CollectionId gocql.UUID
+	MandateId    gocql.UUID
+	Amount       float64
+	Reason       string
+}
+
+func (e DirectDebitRefundedEvent) GetAccountId() gocql.UUID {
+	return e.AccountId
+}
+
+func (e DirectDebitRefundedEvent) GetEventId() gocql.UUID {
+	return e.EventId
+}
+
+func (e DirectDebitRefundedEvent) Apply(account *Account) error {
+	if e.AccountId != account.accountId {
+		return eventAccountMismatched(e, account)
+	}
+	if _, ok := account.collections[e.CollectionId]; !ok {
+		return fmt.Errorf("refunded collection %s is not a collection of account %s", e.CollectionId, account.accountId)
+	}
+	account.balance += e.Amount
+	account.reverse(e.CollectionId, e.EventId)
+	return nil
+}
@@ -0,0 +1,134 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"math"
+
+	"github.com/gocql/gocql"
+)
+
+type ExchangeRate struct {
+	SnapshotId gocql.UUID
+	From       Currency
+	To         Currency
+	Rate       float64
+	Spread     float64
+}
+
+type ExchangeRates interface {
+	Rate(ctx context.Context, from, to Currency) (ExchangeRate, error)
+}
+
+type Exchange struct {
+	ExchangeId    gocql.UUID
+	FromAccountId gocql.UUID
+	ToAccountId   gocql.UUID
+	Debited       float64
+	Credited      float64
+	Rate          ExchangeRate
+}
+
+func (s *AccountService) Exchange(ctx context.Context, fromAccountId, toAccountId gocql.UUID, amount float64) (Exchange, error) {
+	if s.rates == nil {
+		return Exchange{}, NewDomainError("currency exchange is not available")
+	}
+	if fromAccountId == toAccountId {
+		return Exchange{}, NewDomainError("money can not be exchanged within account %s", fromAccountId)
+	}
+	if amount <= 0 {
+		return Exchange{}, NewDomainError("the exchanged amount %f must be positive", amount)
+	}
+	from, err := s.AccountForWithdrawal(ctx, fromAccountId, amount)
+	if err != nil {
+		return Exchange{}, err
+	}
+	to, err := s.GetAccount(ctx, toAccountId)
+	if err != nil {
+		return Exchange{}, err
+	}
+	if from.currency == to.currency {
+		return Exchange{}, NewDomainError("accounts %s and %s are both held in %s", fromAccountId, toAccountId, from.currency)
+	}
+	if from.accountType.Profile().WithdrawalApproval {
+		return Exchange{}, NewDomainError("money can not be exchanged out of %s account %s", from.accountType, fromAccountId)
+	}
+	if err := from.checkWithdrawal(amount); err != nil {
+		return Exchange{}, err
+	}
+	if err := from.currency.checkAmount(amount); err != nil {
+		return Exchange{}, err
+	}
+	if err := to.requireState("receive an exchange", AccountActive, AccountFrozen); err != nil {
+		return Exchange{}, err
+	}
+	if from.approval.WithdrawalThreshold > 0 && amount > from.approval.WithdrawalThreshold {
+		return Exchange{}, NewDomainError("exchanging %f out of account %s exceeds the approval threshold %f", amount, fromAccountId, from.approval.WithdrawalThreshold)
+	}
+	decision, err := from.checkRules(ctx, WithdrawCommand, amount)
+	if err != nil {
+		return Exchange{}, err
+	}
+	rate, err := s.rates.Rate(ctx, from.currency, to.currency)
+	if err != nil {
+		return Exchange{}, err
+	}
+	credited := to.currency.round(amount * rate.Rate * (1 - rate.Spread))
+	if credited <= 0 {
+		return Exchange{}, NewDomainError("exchanging %f %s results in no %s", amount, from.currency, to.currency)
+	}
+	exchange := Exchange{
+		ExchangeId:    gocql.MustRandomUUID(),
+		FromAccountId: fromAccountId,
+		ToAccountId:   toAccountId,
+		Debited:       amount,
+		Credited:      credited,
+		Rate:          rate,
+	}
+	debit := ExchangeDebitedEvent{
+		AccountId:        fromAccountId,
+		EventId:          gocql.TimeUUID(),
+		ExchangeId:       exchange.ExchangeId,
+		Amount:           amount,
+		Currency:         from.currency,
+		CounterAccountId: toAccountId,
+		CounterAmount:    credited,
+		CounterCurrency:  to.currency,
+		Rate:             rate.Rate,
+		Spread:           rate.Spread,
+		SnapshotId:       rate.SnapshotId,
+	}
+	if err := from.emit(ctx, withDecision(decision, debit)...); err != nil {
+		return Exchange{}, err
+	}
+	err = to.emit(ctx, ExchangeCreditedEvent{
+		AccountId:        toAccountId,
+		EventId:          gocql.TimeUUID(),
+		ExchangeId:       exchange.ExchangeId,
+		Amount:           credited,
+		Currency:         to.currency,
+		CounterAccountId: fromAccountId,
+		CounterAmount:    amount,
+		CounterCurrency:  from.currency,
+		Rate:             rate.Rate,
+		Spread:           rate.Spread,
+		SnapshotId:       rate.SnapshotId,
+	})
+	if err != nil {
+		refund := from.emit(ctx, TransactionReversedEvent{
+			AccountId:       fromAccountId,
+			EventId:         gocql.TimeUUID(),
+			ReversedEventId: debit.EventId,
+			Command:         WithdrawCommand,
+			Amount:          amount,
+			Reason:          "crediting the exchange failed",
+		})
+		return Exchange{}, errors.Join(err, refund)
+	}
+	return exchange, nil
+}
+
+func (c Currency) round(amount float64) float64 {
+	factor := math.Pow10(c.MinorUnits())
+	return math.Round(amount*factor) / factor
+}
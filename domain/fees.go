@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"github.com/gocql/gocql"
+)
+
+type FeeKind string
+
+const (
+	WithdrawalFee        FeeKind = "withdrawal"
+	OverdraftUsageFee    FeeKind = "overdraftUsage"
+	MaintenanceFee       FeeKind = "maintenance"
+	OverdraftInterestFee FeeKind = "overdraftInterest"
+)
+
+type FeeSchedule struct {
+	Withdrawal     float64
+	OverdraftUsage float64
+	Maintenance    float64
+	OverdraftRate  float64
+}
+
+type FeeSchedules map[AccountType]FeeSchedule
+
+func (s FeeSchedule) Validate() error {
+	if s.Withdrawal < 0 || s.OverdraftUsage < 0 || s.Maintenance < 0 {
+		return NewDomainError("fees can not be negative")
+	}
+	if s.OverdraftRate < 0 || s.OverdraftRate >= 1 {
+		return NewDomainError("overdraft rate %f must be at least 0 and below 1", s.OverdraftRate)
+	}
+	return nil
+}
+
+func (s FeeSchedule) accrues() bool {
+	return s.OverdraftRate > 0 || s.Maintenance > 0
+}
+
+func (a *Account) FeeSchedule() FeeSchedule {
+	return a.fees[a.accountType]
+}
+
+func (a *Account) AccruedOverdraftInterest() float64 {
+	return a.interest.debitAccrued
+}
+
+func (a *Account) withdrawalFees(amount float64) ([]AccountEvent, float64) {
+	schedule := a.FeeSchedule()
+	var events []AccountEvent
+	total := 0.0
+	charge := func(kind FeeKind, fee float64) {
+		if fee = a.currency.round(fee); fee > 0 {
+			events = append(events, a.feeCharged(kind, fee, ""))
+			total += fee
+		}
+	}
+	charge(WithdrawalFee, schedule.Withdrawal)
+	if a.balance >= 0 && a.balance-amount-total < 0 {
+		charge(OverdraftUsageFee, schedule.OverdraftUsage)
+	}
+	return events, total
+}
+
+func (a *Account) feeCharged(kind FeeKind, amount float64, month string) FeeChargedEvent {
+	return FeeChargedEvent{
+		AccountId: a.accountId,
+		EventId:   gocql.TimeUUID(),
+		Kind:      kind,
+		Amount:    amount,
+		Currency:  a.currency,
+		Month:     month,
+	}
+}
@@ -0,0 +1,96 @@
+package domain
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/gocql/gocql"
+)
+
+type fixedClock struct {
+	now time.Time
+}
+
+func (c fixedClock) Now() time.Time {
+	return c.now
+}
+
+type memoryAccountEventRepository struct {
+	events []AccountEvent
+}
+
+func (r *memoryAccountEventRepository) Write(ctx context.Context, events ...AccountEvent) error {
+	r.events = append(r.events, events...)
+	return nil
+}
+
+func (r *memoryAccountEventRepository) ReadEvents(ctx context.Context, accountId gocql.UUID, pageState []byte, pageSize int) (AccountEventPage, error) {
+	var events []AccountEvent
+	for _, event := range r.events {
+		if event.GetAccountId() == accountId {
+			events = append(events, event)
+		}
+	}
+	offset := 0
+	if len(pageState) > 0 {
+		offset, _ = strconv.Atoi(string(pageState))
+	}
+	page := AccountEventPage{}
+	if end := offset + pageSize; end < len(events) {
+		page.Events = events[offset:end]
+		page.NextPageState = []byte(strconv.Itoa(end))
+	} else if offset < len(events) {
+		page.Events = events[offset:]
+	}
+	return page, nil
+}
+
+func (r *memoryAccountEventRepository) ReadAllAccountIds(ctx context.Context) ([]gocql.UUID, error) {
+	seen := map[gocql.UUID]bool{}
+	var ids []gocql.UUID
+	for _, event := range r.events {
+		if !seen[event.GetAccountId()] {
+			seen[event.GetAccountId()] = true
+			ids = append(ids, event.GetAccountId())
+		}
+	}
+	return ids, nil
+}
+
+func testAccount(now time.Time) Account {
+	acc := Account{repo: &memoryAccountEventRepository{}, clock: fixedClock{now: now}, accountId: gocql.TimeUUID()}
+	acc.apply(AccountCreatedEvent{AccountId: acc.accountId, EventId: gocql.UUIDFromTime(now.AddDate(-1, 0, 0)), Currency: DefaultCurrency})
+	return acc
+}
+
+type memoryCustomerEventRepository struct {
+	events []CustomerEvent
+}
+
+func (r *memoryCustomerEventRepository) Write(ctx context.Context, events ...CustomerEvent) error {
+	r.events = append(r.events, events...)
+	return nil
+}
+
+func (r *memoryCustomerEventRepository) ReadCustomerEvents(ctx context.Context, customerId gocql.UUID) ([]CustomerEvent, error) {
+	var events []CustomerEvent
+	for _, event := range r.events {
+		if event.GetCustomerId() == customerId {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+func (r *memoryCustomerEventRepository) ReadAllCustomerIds(ctx context.Context) ([]gocql.UUID, error) {
+	seen := map[gocql.UUID]bool{}
+	var ids []gocql.UUID
+	for _, event := range r.events {
+		if !seen[event.GetCustomerId()] {
+			seen[event.GetCustomerId()] = true
+			ids = append(ids, event.GetCustomerId())
+		}
+	}
+	return ids, nil
+}
@@ -0,0 +1,128 @@
+package domain
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/gocql/gocql"
+)
+
+const DefaultHoldDuration = 7 * 24 * time.Hour
+
+type Hold struct {
+	HoldId    gocql.UUID
+	Amount    float64
+	ExpiresAt time.Time
+}
+
+func (a *Account) Holds() []Hold {
+	holds := make([]Hold, 0, len(a.holds))
+	for _, hold := range a.holds {
+		holds = append(holds, hold)
+	}
+	sort.Slice(holds, func(i, j int) bool {
+		return holds[i].ExpiresAt.Before(holds[j].ExpiresAt)
+	})
+	return holds
+}
+
+func (a *Account) PlaceHold(ctx context.Context, amount float64, expiresAt time.Time) (Hold, error) {
+	if !a.accountType.Profile().Holds {
+		return Hold{}, NewDomainError("holds can not be placed on %s accounts", a.accountType)
+	}
+	if amount <= 0 {
+		return Hold{}, NewDomainError("a hold of %f must be positive", amount)
+	}
+	if err := a.currency.checkAmount(amount); err != nil {
+		return Hold{}, err
+	}
+	if expiresAt.IsZero() {
+		expiresAt = a.clock.Now().Add(DefaultHoldDuration)
+	}
+	if !expiresAt.After(a.clock.Now()) {
+		return Hold{}, NewDomainError("a hold can not expire in the past at %s", expiresAt)
+	}
+	if a.approval.WithdrawalThreshold > 0 && amount > a.approval.WithdrawalThreshold {
+		return Hold{}, NewDomainError("the hold of %f exceeds the approval threshold %f", amount, a.approval.WithdrawalThreshold)
+	}
+	if err := a.checkWithdrawal(amount); err != nil {
+		return Hold{}, err
+	}
+	decision, err := a.checkRules(ctx, WithdrawCommand, amount)
+	if err != nil {
+		return Hold{}, err
+	}
+	e := HoldPlacedEvent{
+		AccountId: a.accountId,
+		EventId:   gocql.TimeUUID(),
+		HoldId:    gocql.MustRandomUUID(),
+		Amount:    amount,
+		ExpiresAt: expiresAt,
+	}
+	if err := a.emit(ctx, withDecision(decision, e)...); err != nil {
+		return Hold{}, err
+	}
+	return a.holds[e.HoldId], nil
+}
+
+func (a *Account) CaptureHold(ctx context.Context, holdId gocql.UUID, amount float64) error {
+	if err := a.requireState("capture a hold", AccountActive); err != nil {
+		return err
+	}
+	hold, err := a.hold(holdId)
+	if err != nil {
+		return err
+	}
+	if amount <= 0 || amount > hold.Amount {
+		return NewDomainError("captured amount %f must be positive and not exceed the hold of %f", amount, hold.Amount)
+	}
+	if err := a.currency.checkAmount(amount); err != nil {
+		return err
+	}
+	fees, _ := a.withdrawalFees(amount)
+	return a.emit(ctx, append([]AccountEvent{HoldCapturedEvent{
+		AccountId: a.accountId,
+		EventId:   gocql.TimeUUID(),
+		HoldId:    holdId,
+		Amount:    amount,
+	}}, fees...)...)
+}
+
+func (a *Account) ReleaseHold(ctx context.Context, holdId gocql.UUID) error {
+	if _, err := a.hold(holdId); err != nil {
+		return err
+	}
+	return a.emit(ctx, HoldReleasedEvent{
+		AccountId: a.accountId,
+		EventId:   gocql.TimeUUID(),
+		HoldId:    holdId,
+	})
+}
+
+func (a *Account) ExpireHolds(ctx context.Context) (int, error) {
+	now := a.clock.Now()
+	var events []AccountEvent
+	for _, hold := range a.Holds() {
+		if hold.ExpiresAt.After(now) {
+			break
+		}
+		events = append(events, HoldExpiredEvent{
+			AccountId: a.accountId,
+			EventId:   gocql.TimeUUID(),
+			HoldId:    hold.HoldId,
+		})
+	}
+	if len(events) == 0 {
+		return 0, nil
+	}
+	return len(events), a.emit(ctx, events...)
+}
+
+func (a *Account) hold(holdId gocql.UUID) (Hold, error) {
+	hold, ok := a.holds[holdId]
+	if !ok {
+		return hold, NewDomainError("hold %s does not exist on account %s", holdId, a.accountId)
+	}
+	return hold, nil
+}
@@ -0,0 +1,235 @@
+package domain
+
+import (
+	"context"
+	"sort"
+
+	"github.com/gocql/gocql"
+)
+
+type HolderPermission string
+
+const (
+	ViewPermission     HolderPermission = "view"
+	DepositPermission  HolderPermission = "deposit"
+	WithdrawPermission HolderPermission = "withdraw"
+	FullPermission     HolderPermission = "full"
+)
+
+var permissionLevels = map[HolderPermission]int{
+	ViewPermission:     1,
+	DepositPermission:  2,
+	WithdrawPermission: 3,
+	FullPermission:     4,
+}
+
+func ParseHolderPermission(value string) (HolderPermission, error) {
+	permission := HolderPermission(value)
+	if _, ok := permissionLevels[permission]; !ok {
+		return "", NewDomainError("%s is not a valid holder permission, use %s, %s, %s or %s", value, ViewPermission, DepositPermission, WithdrawPermission, FullPermission)
+	}
+	return permission, nil
+}
+
+func (p HolderPermission) allows(required HolderPermission) bool {
+	return permissionLevels[p] >= permissionLevels[required]
+}
+
+type Holder struct {
+	CustomerId      gocql.UUID
+	Permission      HolderPermission
+	WithdrawalLimit float64
+}
+
+func (h Holder) validate(currency Currency) error {
+	if _, err := ParseHolderPermission(string(h.Permission)); err != nil {
+		return err
+	}
+	if h.WithdrawalLimit < 0 {
+		return NewDomainError("withdrawal limit %f of holder %s can not be negative", h.WithdrawalLimit, h.CustomerId)
+	}
+	if h.WithdrawalLimit > 0 && h.Permission != WithdrawPermission {
+		return NewDomainError("a withdrawal limit only applies to holders with %s permission", WithdrawPermission)
+	}
+	return currency.checkAmount(h.WithdrawalLimit)
+}
+
+func (a *Account) Holders() []Holder {
+	holders := make([]Holder, 0, len(a.holders))
+	for _, holder := range a.holders {
+		holders = append(holders, holder)
+	}
+	sort.Slice(holders, func(i, j int) bool {
+		return holders[i].CustomerId.String() < holders[j].CustomerId.String()
+	})
+	return holders
+}
+
+func (a *Account) Holder(customerId gocql.UUID) (Holder, bool) {
+	holder, ok := a.holders[customerId]
+	return holder, ok
+}
+
+func (a *Account) addHolder(ctx context.Context, holder Holder) error {
+	if caller := CallerFrom(ctx); caller.IsCustomer() {
+		return NewPermissionDeniedError("%s can not add holders to account %s", caller.Name, a.accountId)
+	}
+	if err := a.requireState("add a holder", AccountPending, AccountActive, AccountFrozen); err != nil {
+		return err
+	}
+	if _, ok := a.holders[holder.CustomerId]; ok {
+		return NewDomainError("customer %s already holds account %s", holder.CustomerId, a.accountId)
+	}
+	if err := holder.validate(a.currency); err != nil {
+		return err
+	}
+	return a.emit(ctx, HolderAddedEvent{
+		AccountId:       a.accountId,
+		EventId:         gocql.TimeUUID(),
+		CustomerId:      holder.CustomerId,
+		Permission:      holder.Permission,
+		WithdrawalLimit: holder.WithdrawalLimit,
+	})
+}
+
+func (a *Account) changeHolderPermission(ctx context.Context, holder Holder) error {
+	current, ok := a.holders[holder.CustomerId]
+	if !ok {
+		return NewDomainError("customer %s does not hold account %s", holder.CustomerId, a.accountId)
+	}
+	if err := holder.validate(a.currency); err != nil {
+		return err
+	}
+	if current == holder {
+		return nil
+	}
+	if current.Permission == FullPermission && holder.Permission != FullPermission {
+		if err := a.checkRemainingFullHolder(holder.CustomerId); err != nil {
+			return err
+		}
+	}
+	return a.emit(ctx, HolderPermissionChangedEvent{
+		AccountId:       a.accountId,
+		EventId:         gocql.TimeUUID(),
+		CustomerId:      holder.CustomerId,
+		Permission:      holder.Permission,
+		WithdrawalLimit: holder.WithdrawalLimit,
+	})
+}
+
+func (a *Account) removeHolder(ctx context.Context, customerId gocql.UUID) error {
+	holder, ok := a.holders[customerId]
+	if !ok {
+		return NewDomainError("customer %s does not hold account %s", customerId, a.accountId)
+	}
+	if holder.Permission == FullPermission {
+		if err := a.checkRemainingFullHolder(customerId); err != nil {
+			return err
+		}
+	}
+	return a.emit(ctx, HolderRemovedEvent{
+		AccountId:  a.accountId,
+		EventId:    gocql.TimeUUID(),
+		CustomerId: customerId,
+	})
+}
+
+func (a *Account) checkRemainingFullHolder(customerId gocql.UUID) error {
+	for id, holder := range a.holders {
+		if id != customerId && holder.Permission == FullPermission {
+			return nil
+		}
+	}
+	return NewDomainError("customer %s is the last holder with %s permission of account %s", customerId, FullPermission, a.accountId)
+}
+
+func (a *Account) authorize(caller Caller, permission HolderPermission, amount float64) error {
+	if !caller.IsCustomer() {
+		return nil
+	}
+	holder, ok := a.holders[caller.CustomerId]
+	if !ok {
+		return NewPermissionDeniedError("%s is not a holder of account %s", caller.Name, a.accountId)
+	}
+	if !holder.Permission.allows(permission) {
+		return NewPermissionDeniedError("%s may only %s on account %s", caller.Name, holder.Permission, a.accountId)
+	}
+	if holder.Permission == WithdrawPermission && holder.WithdrawalLimit > 0 && amount > holder.WithdrawalLimit {
+		return NewPermissionDeniedError("%s may withdraw at most %f at once from account %s", caller.Name, holder.WithdrawalLimit, a.accountId)
+	}
+	return nil
+}
+
+func (s *AccountService) AccountFor(ctx context.Context, accountId gocql.UUID, permission HolderPermission) (Account, error) {
+	return s.authorizedAccount(ctx, accountId, permission, 0)
+}
+
+func (s *AccountService) AccountForWithdrawal(ctx context.Context, accountId gocql.UUID, amount float64) (Account, error) {
+	return s.authorizedAccount(ctx, accountId, WithdrawPermission, amount)
+}
+
+func (s *AccountService) AccountForHistory(ctx context.Context, accountId gocql.UUID) (Account, error) {
+	acc, err := s.LoadAccount(ctx, accountId)
+	if err != nil {
+		return Account{}, err
+	}
+	if err := acc.authorize(CallerFrom(ctx), ViewPermission, 0); err != nil {
+		return Account{}, err
+	}
+	return acc, nil
+}
+
+func (s *AccountService) authorizedAccount(ctx context.Context, accountId gocql.UUID, permission HolderPermission, amount float64) (Account, error) {
+	acc, err := s.GetAccount(ctx, accountId)
+	if err != nil {
+		return Account{}, err
+	}
+	if err := acc.authorize(CallerFrom(ctx), permission, amount); err != nil {
+		return Account{}, err
+	}
+	return acc, nil
+}
+
+func (s *AccountService) AddHolder(ctx context.Context, accountId gocql.UUID, holder Holder) error {
+	acc, err := s.AccountFor(ctx, accountId, FullPermission)
+	if err != nil {
+		return err
+	}
+	customer, err := s.accountCustomer(ctx, holder.CustomerId)
+	if err != nil {
+		return err
+	}
+	if err := acc.addHolder(ctx, holder); err != nil {
+		return err
+	}
+	if customer.owns(accountId) {
+		return nil
+	}
+	return customer.LinkAccount(ctx, accountId)
+}
+
+func (s *AccountService) ChangeHolderPermission(ctx context.Context, accountId gocql.UUID, holder Holder) error {
+	acc, err := s.AccountFor(ctx, accountId, FullPermission)
+	if err != nil {
+		return err
+	}
+	return acc.changeHolderPermission(ctx, holder)
+}
+
+func (s *AccountService) RemoveHolder(ctx context.Context, accountId, customerId gocql.UUID) error {
+	acc, err := s.AccountFor(ctx, accountId, FullPermission)
+	if err != nil {
+		return err
+	}
+	if err := acc.removeHolder(ctx, customerId); err != nil {
+		return err
+	}
+	if s.customers == nil {
+		return nil
+	}
+	customer, err := s.customers.GetCustomer(ctx, customerId)
+	if err != nil {
+		return err
+	}
+	return customer.UnlinkAccount(ctx, accountId)
+}
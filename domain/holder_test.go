@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gocql/gocql"
+)
+
+func TestOnlyStaffCreatesCustomersAndAddsHolders(t *testing.T) {
+	tests := []struct {
+		name    string
+		caller  Caller
+		allowed bool
+	}{
+		{"staff", Caller{Name: "alice"}, true},
+		{"customer", Caller{Name: "carol", CustomerId: gocql.TimeUUID()}, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := WithCaller(context.Background(), test.caller)
+			customers := NewCustomerService(&memoryCustomerEventRepository{}, OperationTimeouts{})
+			_, err := customers.CreateCustomer(ctx, NewCustomer{Name: "Dave", Address: Address{Street: "Main Street 1", City: "Berlin", Country: "DE"}})
+			if _, denied := err.(*PermissionDeniedError); denied == test.allowed || test.allowed && err != nil {
+				t.Fatalf("expected creating a customer to be allowed %t, got %v", test.allowed, err)
+			}
+			acc := testAccount(time.Now())
+			err = acc.addHolder(ctx, Holder{CustomerId: gocql.TimeUUID(), Permission: ViewPermission})
+			if _, denied := err.(*PermissionDeniedError); denied == test.allowed || test.allowed && err != nil {
+				t.Fatalf("expected adding a holder to be allowed %t, got %v", test.allowed, err)
+			}
+		})
+	}
+}
+
+func TestOnlyStaffSetsLimitsPoliciesAndInterest(t *testing.T) {
+	commands := []struct {
+		name string
+		run  func(ctx context.Context, acc *Account) error
+	}{
+		{"limit", func(ctx context.Context, acc *Account) error { return acc.SetNewLimit(ctx, -100) }},
+		{"limit policies", func(ctx context.Context, acc *Account) error {
+			return acc.SetLimitPolicies(ctx, LimitPolicies{MaxDailyWithdrawal: 500})
+		}},
+		{"interest terms", func(ctx context.Context, acc *Account) error {
+			return acc.SetInterestTerms(ctx, InterestTerms{Rate: 0.02, DayCount: Actual360})
+		}},
+	}
+	for _, command := range commands {
+		t.Run(command.name, func(t *testing.T) {
+			acc := testAccount(time.Now())
+			customer := WithCaller(context.Background(), Caller{Name: "carol", CustomerId: gocql.TimeUUID()})
+			if _, denied := command.run(customer, &acc).(*PermissionDeniedError); !denied {
+				t.Fatalf("expected a customer to be denied setting the %s", command.name)
+			}
+			staff := WithCaller(context.Background(), Caller{Name: "alice"})
+			if err := command.run(staff, &acc); err != nil {
+				t.Fatalf("expected staff to set the %s, got %v", command.name, err)
+			}
+		})
+	}
+}
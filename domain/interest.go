@@ -0,0 +1,225 @@
+package domain
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/gocql/gocql"
+)
+
+type DayCountConvention string
+
+const (
+	Actual365 DayCountConvention = "ACT/365"
+	Actual360 DayCountConvention = "ACT/360"
+	Thirty360 DayCountConvention = "30/360"
+)
+
+const day = 24 * time.Hour
+
+func ParseDayCountConvention(value string) (DayCountConvention, error) {
+	switch convention := DayCountConvention(value); convention {
+	case "":
+		return Actual365, nil
+	case Actual365, Actual360, Thirty360:
+		return convention, nil
+	default:
+		return "", NewDomainError("%s is not a supported day count convention, use %s, %s or %s", value, Actual365, Actual360, Thirty360)
+	}
+}
+
+func (c DayCountConvention) yearFraction(start, end time.Time) float64 {
+	switch c {
+	case Thirty360:
+		d1, d2 := start.Day(), end.Day()
+		if d1 == 31 {
+			d1 = 30
+		}
+		if d2 == 31 && d1 == 30 {
+			d2 = 30
+		}
+		days := 360*(end.Year()-start.Year()) + 30*(int(end.Month())-int(start.Month())) + d2 - d1
+		return float64(days) / 360
+	case Actual360:
+		return end.Sub(start).Hours() / 24 / 360
+	default:
+		return end.Sub(start).Hours() / 24 / 365
+	}
+}
+
+type InterestTerms struct {
+	Rate     float64
+	DayCount DayCountConvention
+}
+
+type interestState struct {
+	terms          InterestTerms
+	accruedThrough time.Time
+	accrued        float64
+	debitAccrued   float64
+}
+
+type balancePoint struct {
+	at      time.Time
+	balance float64
+}
+
+func (a *Account) InterestTerms() InterestTerms {
+	return a.interest.terms
+}
+
+func (a *Account) AccruedInterest() float64 {
+	return a.interest.accrued
+}
+
+func (a *Account) SetInterestTerms(ctx context.Context, terms InterestTerms) error {
+	if caller := CallerFrom(ctx); caller.IsCustomer() {
+		return NewPermissionDeniedError("%s can not set the interest terms of account %s", caller.Name, a.accountId)
+	}
+	if err := a.requireState("set the interest rate", AccountActive, AccountFrozen); err != nil {
+		return err
+	}
+	if terms.Rate < 0 || terms.Rate >= 1 {
+		return NewDomainError("interest rate %f must be at least 0 and below 1", terms.Rate)
+	}
+	dayCount, err := ParseDayCountConvention(string(terms.DayCount))
+	if err != nil {
+		return err
+	}
+	events := append(a.accrualEvents(a.clock.Now()), InterestRateSetEvent{
+		AccountId: a.accountId,
+		EventId:   gocql.TimeUUID(),
+		Rate:      terms.Rate,
+		DayCount:  dayCount,
+	})
+	return a.emit(ctx, events...)
+}
+
+func (a *Account) AccrueInterest(ctx context.Context) (int, error) {
+	events := a.accrualEvents(a.clock.Now())
+	if len(events) == 0 {
+		return 0, nil
+	}
+	return len(events), a.emit(ctx, events...)
+}
+
+func (a *Account) accrualEvents(now time.Time) []AccountEvent {
+	schedule := a.FeeSchedule()
+	through := startOfDay(now).Add(-day)
+	accruedThrough := a.interest.accruedThrough
+	if accruedThrough.IsZero() {
+		if !schedule.accrues() {
+			return nil
+		}
+		accruedThrough = through.Add(-day)
+	}
+	accrued := a.interest.accrued
+	debitAccrued := a.interest.debitAccrued
+	capitalized := 0.0
+	var events []AccountEvent
+	for start := accruedThrough.Add(day); !start.After(through); {
+		end := endOfMonth(start)
+		if end.After(through) {
+			end = through
+		}
+		amount, debitAmount := 0.0, 0.0
+		for d := start; !d.After(end); d = d.Add(day) {
+			balance := a.closingBalance(d) + capitalized
+			fraction := a.interest.terms.DayCount.yearFraction(d, d.Add(day))
+			if balance > 0 {
+				amount += balance * a.interest.terms.Rate * fraction
+			} else if balance < 0 {
+				debitAmount -= balance * schedule.OverdraftRate * fraction
+			}
+		}
+		monthEnd := end.Equal(endOfMonth(end))
+		if a.interest.terms.Rate > 0 || amount != 0 || debitAmount != 0 || monthEnd {
+			accrued += amount
+			debitAccrued += debitAmount
+			events = append(events, InterestAccruedEvent{
+				AccountId:   a.accountId,
+				EventId:     gocql.TimeUUID(),
+				From:        start,
+				Through:     end,
+				Amount:      amount,
+				Rate:        a.interest.terms.Rate,
+				DayCount:    a.interest.terms.DayCount,
+				DebitAmount: debitAmount,
+				DebitRate:   schedule.OverdraftRate,
+			})
+		}
+		if monthEnd {
+			month := end.Format("2006-01")
+			if credited := a.currency.round(accrued); credited != 0 {
+				accrued -= credited
+				capitalized += credited
+				events = append(events, InterestCreditedEvent{
+					AccountId: a.accountId,
+					EventId:   gocql.TimeUUID(),
+					Amount:    credited,
+					Month:     month,
+				})
+			}
+			if charged := a.currency.round(debitAccrued); charged > 0 {
+				debitAccrued -= charged
+				capitalized -= charged
+				events = append(events, a.feeCharged(OverdraftInterestFee, charged, month))
+			}
+			fee := a.currency.round(schedule.Maintenance)
+			if balance := a.closingBalance(end) + capitalized; !a.accountType.Profile().Overdraft && fee > balance {
+				fee = math.Max(balance, 0)
+			}
+			if fee > 0 && a.state != AccountPending {
+				capitalized -= fee
+				events = append(events, a.feeCharged(MaintenanceFee, fee, month))
+			}
+		}
+		start = end.Add(day)
+	}
+	return events
+}
+
+func (a *Account) closingBalance(d time.Time) float64 {
+	balance := 0.0
+	for _, point := range a.balanceHistory {
+		if !point.at.Before(d.Add(day)) {
+			break
+		}
+		balance = point.balance
+	}
+	return balance
+}
+
+func (a *Account) trackBalance(at time.Time) {
+	if n := len(a.balanceHistory); n > 0 && a.balanceHistory[n-1].balance == a.balance {
+		return
+	}
+	a.balanceHistory = append(a.balanceHistory, balancePoint{at: at, balance: a.balance})
+	a.pruneBalanceHistory()
+}
+
+func (a *Account) pruneBalanceHistory() {
+	keepFrom := len(a.balanceHistory) - 1
+	if keepFrom < 0 {
+		return
+	}
+	cutoff := a.interest.accruedThrough.Add(day)
+	if a.interest.accruedThrough.IsZero() {
+		cutoff = startOfDay(a.balanceHistory[keepFrom].at).Add(-day)
+	}
+	for keepFrom > 0 && !a.balanceHistory[keepFrom].at.Before(cutoff) {
+		keepFrom--
+	}
+	if keepFrom > 0 {
+		a.balanceHistory = append(a.balanceHistory[:0], a.balanceHistory[keepFrom:]...)
+	}
+}
+
+func startOfDay(t time.Time) time.Time {
+	return t.UTC().Truncate(day)
+}
+
+func endOfMonth(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, time.UTC)
+}
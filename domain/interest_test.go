@@ -0,0 +1,121 @@
+package domain
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/gocql/gocql"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestYearFraction(t *testing.T) {
+	tests := []struct {
+		convention DayCountConvention
+		start      time.Time
+		end        time.Time
+		days       float64
+		basis      float64
+	}{
+		{Actual365, date(2023, 1, 1), date(2023, 1, 31), 30, 365},
+		{Actual365, date(2024, 1, 1), date(2025, 1, 1), 366, 365},
+		{Actual365, date(2024, 2, 28), date(2024, 3, 1), 2, 365},
+		{Actual360, date(2023, 1, 1), date(2023, 1, 31), 30, 360},
+		{Actual360, date(2023, 1, 1), date(2024, 1, 1), 365, 360},
+		{Actual360, date(2024, 2, 28), date(2024, 3, 1), 2, 360},
+		{Thirty360, date(2023, 1, 1), date(2023, 1, 31), 30, 360},
+		{Thirty360, date(2023, 1, 30), date(2023, 1, 31), 0, 360},
+		{Thirty360, date(2023, 1, 31), date(2023, 2, 1), 1, 360},
+		{Thirty360, date(2023, 1, 31), date(2023, 2, 28), 28, 360},
+		{Thirty360, date(2023, 1, 31), date(2023, 3, 31), 60, 360},
+		{Thirty360, date(2023, 2, 28), date(2023, 3, 1), 3, 360},
+		{Thirty360, date(2023, 2, 28), date(2023, 3, 31), 33, 360},
+		{Thirty360, date(2024, 1, 1), date(2025, 1, 1), 360, 360},
+	}
+	for _, test := range tests {
+		name := string(test.convention) + " " + test.start.Format(time.DateOnly) + " to " + test.end.Format(time.DateOnly)
+		t.Run(name, func(t *testing.T) {
+			expected := test.days / test.basis
+			if got := test.convention.yearFraction(test.start, test.end); math.Abs(got-expected) > 1e-12 {
+				t.Fatalf("expected %f, got %f", expected, got)
+			}
+		})
+	}
+}
+
+func TestThirty360AccruesThirtyDaysPerMonth(t *testing.T) {
+	for _, month := range []time.Time{date(2023, 1, 1), date(2023, 2, 1), date(2024, 2, 1), date(2023, 4, 1), date(2023, 12, 1)} {
+		t.Run(month.Format("2006-01"), func(t *testing.T) {
+			total := 0.0
+			for d := month; !d.After(endOfMonth(month)); d = d.Add(day) {
+				total += Thirty360.yearFraction(d, d.Add(day))
+			}
+			if math.Abs(total-30.0/360) > 1e-12 {
+				t.Fatalf("expected a month to accrue 30/360, got %f", total*360)
+			}
+		})
+	}
+}
+
+func TestAccrualEventsRoundCreditedInterest(t *testing.T) {
+	tests := []struct {
+		name       string
+		currency   Currency
+		dayCount   DayCountConvention
+		now        time.Time
+		accrued    float64
+		credited   float64
+		remainder  float64
+		periodDays int
+	}{
+		{"ACT/365 month end", DefaultCurrency, Actual365, date(2023, 2, 1).Add(10 * time.Hour), 1000 * 0.05 * 31 / 365, 4.25, 1000*0.05*31/365 - 4.25, 31},
+		{"ACT/360 month end", DefaultCurrency, Actual360, date(2023, 2, 1).Add(10 * time.Hour), 1000 * 0.05 * 31 / 360, 4.31, 1000*0.05*31/360 - 4.31, 31},
+		{"30/360 month end", DefaultCurrency, Thirty360, date(2023, 2, 1).Add(10 * time.Hour), 1000 * 0.05 * 30 / 360, 4.17, 1000*0.05*30/360 - 4.17, 31},
+		{"minor units of JPY", Currency("JPY"), Actual365, date(2023, 2, 1).Add(10 * time.Hour), 1000 * 0.05 * 31 / 365, 4, 1000*0.05*31/365 - 4, 31},
+		{"within the month", DefaultCurrency, Actual365, date(2023, 1, 16).Add(10 * time.Hour), 1000 * 0.05 * 15 / 365, 0, 1000 * 0.05 * 15 / 365, 15},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			acc := Account{repo: &memoryAccountEventRepository{}, clock: fixedClock{now: test.now}, accountId: gocql.TimeUUID()}
+			events := []AccountEvent{
+				AccountCreatedEvent{AccountId: acc.accountId, EventId: gocql.UUIDFromTime(date(2022, 12, 1)), Currency: test.currency},
+				MoneyDipositedEvent{AccountId: acc.accountId, EventId: gocql.UUIDFromTime(date(2022, 12, 31).Add(12 * time.Hour)), Amount: 1000, Currency: test.currency},
+				InterestRateSetEvent{AccountId: acc.accountId, EventId: gocql.UUIDFromTime(date(2023, 1, 1).Add(time.Second)), Rate: 0.05, DayCount: test.dayCount},
+			}
+			for _, event := range events {
+				if err := acc.apply(event); err != nil {
+					t.Fatal(err)
+				}
+			}
+			credited := 0.0
+			for _, event := range acc.accrualEvents(test.now) {
+				switch e := event.(type) {
+				case InterestAccruedEvent:
+					if days := int(e.Through.Sub(e.From)/day) + 1; days != test.periodDays {
+						t.Fatalf("expected an accrual period of %d days, got %d", test.periodDays, days)
+					}
+					if math.Abs(e.Amount-test.accrued) > 1e-9 {
+						t.Fatalf("expected %f accrued, got %f", test.accrued, e.Amount)
+					}
+				case InterestCreditedEvent:
+					credited = e.Amount
+				}
+				if err := acc.apply(event); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if credited != test.credited {
+				t.Fatalf("expected %f credited, got %f", test.credited, credited)
+			}
+			if math.Abs(acc.AccruedInterest()-test.remainder) > 1e-9 {
+				t.Fatalf("expected %f to remain accrued, got %f", test.remainder, acc.AccruedInterest())
+			}
+			if acc.Balance() != 1000+test.credited {
+				t.Fatalf("expected a balance of %f, got %f", 1000+test.credited, acc.Balance())
+			}
+		})
+	}
+}
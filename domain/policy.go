@@ -0,0 +1,108 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/gocql/gocql"
+)
+
+type LimitPolicies struct {
+	MaxDailyWithdrawal   float64
+	MaxHourlyWithdrawals int
+	MaxTransactionAmount float64
+}
+
+type recentTransaction struct {
+	eventId    gocql.UUID
+	at         time.Time
+	amount     float64
+	withdrawal bool
+}
+
+type RecentActivity struct {
+	WithdrawnLastDay    float64
+	WithdrawalsLastHour int
+	DepositedLastDay    float64
+	DepositsLastHour    int
+}
+
+const recentTransactionWindow = 24 * time.Hour
+
+func (a *Account) LimitPolicies() LimitPolicies {
+	return a.policies
+}
+
+func (a *Account) SetLimitPolicies(ctx context.Context, policies LimitPolicies) error {
+	if caller := CallerFrom(ctx); caller.IsCustomer() {
+		return NewPermissionDeniedError("%s can not set the limit policies of account %s", caller.Name, a.accountId)
+	}
+	if err := a.requireState("set limit policies", AccountActive); err != nil {
+		return err
+	}
+	if policies.MaxDailyWithdrawal < 0 || policies.MaxHourlyWithdrawals < 0 || policies.MaxTransactionAmount < 0 {
+		return NewDomainError("limit policies %+v can not be negative", policies)
+	}
+	return a.emit(ctx, LimitPoliciesSetEvent{
+		AccountId: a.accountId,
+		EventId:   gocql.TimeUUID(),
+		Policies:  policies,
+	})
+}
+
+func (a *Account) checkLimitPolicies(amount float64) error {
+	p := a.policies
+	if p.MaxTransactionAmount > 0 && amount > p.MaxTransactionAmount {
+		return NewDomainError("the withdrawn amount %f exceeds the maximum transaction amount %f", amount, p.MaxTransactionAmount)
+	}
+	recent := a.RecentActivity()
+	if p.MaxDailyWithdrawal > 0 && recent.WithdrawnLastDay+amount > p.MaxDailyWithdrawal {
+		return NewDomainError("the withdrawn amount %f would exceed the daily maximum of %f, %f already withdrawn", amount, p.MaxDailyWithdrawal, recent.WithdrawnLastDay)
+	}
+	if p.MaxHourlyWithdrawals > 0 && recent.WithdrawalsLastHour+1 > p.MaxHourlyWithdrawals {
+		return NewDomainError("the maximum of %d withdrawals per hour is reached", p.MaxHourlyWithdrawals)
+	}
+	return nil
+}
+
+func (a *Account) RecentActivity() RecentActivity {
+	now := a.clock.Now()
+	recent := RecentActivity{}
+	for _, t := range a.recentTransactions {
+		lastDay := t.at.After(now.Add(-recentTransactionWindow))
+		lastHour := t.at.After(now.Add(-time.Hour))
+		switch {
+		case t.withdrawal && lastDay:
+			recent.WithdrawnLastDay += t.amount
+			if lastHour {
+				recent.WithdrawalsLastHour++
+			}
+		case lastDay:
+			recent.DepositedLastDay += t.amount
+			if lastHour {
+				recent.DepositsLastHour++
+			}
+		}
+	}
+	return recent
+}
+
+func (a *Account) recordTransaction(eventId gocql.UUID, amount float64, withdrawal bool) {
+	at := eventId.Time()
+	kept := a.recentTransactions[:0]
+	for _, t := range a.recentTransactions {
+		if t.at.After(at.Add(-recentTransactionWindow)) {
+			kept = append(kept, t)
+		}
+	}
+	a.recentTransactions = append(kept, recentTransaction{eventId: eventId, at: at, amount: amount, withdrawal: withdrawal})
+}
+
+func (a *Account) forgetTransaction(eventId gocql.UUID) {
+	for i, t := range a.recentTransactions {
+		if t.eventId == eventId {
+			a.recentTransactions = append(a.recentTransactions[:i], a.recentTransactions[i+1:]...)
+			return
+		}
+	}
+}
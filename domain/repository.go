@@ -1,11 +1,157 @@
 package domain
 
 import (
+	"context"
+	"time"
+
 	"github.com/gocql/gocql"
 )
 
 type AccountEventRepository interface {
-	Write(event AccountEvent) error
-	ReadAllEvents(accountId gocql.UUID) ([]AccountEvent, error)
-	ReadAllAccountIds() ([]gocql.UUID, error)
+	Write(ctx context.Context, events ...AccountEvent) error
+	ReadEvents(ctx context.Context, accountId gocql.UUID, pageState []byte, pageSize int) (AccountEventPage, error)
+	ReadAllAccountIds(ctx context.Context) ([]gocql.UUID, error)
+}
+
+type CustomerEventRepository interface {
+	Write(ctx context.Context, events ...CustomerEvent) error
+	ReadCustomerEvents(ctx context.Context, customerId gocql.UUID) ([]CustomerEvent, error)
+	ReadAllCustomerIds(ctx context.Context) ([]gocql.UUID, error)
+}
+
+type AccountNumberRepository interface {
+	Register(ctx context.Context, number AccountNumber, accountId gocql.UUID) (bool, error)
+	Lookup(ctx context.Context, number AccountNumber) (gocql.UUID, bool, error)
+	Release(ctx context.Context, number AccountNumber, accountId gocql.UUID) error
+}
+
+type AccountEventPage struct {
+	Events        []AccountEvent
+	NextPageState []byte
+}
+
+const DefaultPageSize = 500
+
+func ForEachEvent(ctx context.Context, repo AccountEventRepository, accountId gocql.UUID, pageSize int, fn func(event AccountEvent) error) error {
+	var pageState []byte
+	for {
+		page, err := repo.ReadEvents(ctx, accountId, pageState, pageSize)
+		if err != nil {
+			return err
+		}
+		for _, event := range page.Events {
+			if err := fn(event); err != nil {
+				return err
+			}
+		}
+		if len(page.NextPageState) == 0 {
+			return nil
+		}
+		pageState = page.NextPageState
+	}
+}
+
+type OperationTimeouts struct {
+	Read  time.Duration
+	Write time.Duration
+}
+
+type timeoutRepository struct {
+	repo     AccountEventRepository
+	timeouts OperationTimeouts
+}
+
+func withTimeouts(repo AccountEventRepository, timeouts OperationTimeouts) AccountEventRepository {
+	return &timeoutRepository{
+		repo:     repo,
+		timeouts: timeouts,
+	}
+}
+
+func (r *timeoutRepository) Write(ctx context.Context, events ...AccountEvent) error {
+	ctx, cancel := withOptionalTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	return r.repo.Write(ctx, events...)
+}
+
+func (r *timeoutRepository) ReadEvents(ctx context.Context, accountId gocql.UUID, pageState []byte, pageSize int) (AccountEventPage, error) {
+	ctx, cancel := withOptionalTimeout(ctx, r.timeouts.Read)
+	defer cancel()
+	return r.repo.ReadEvents(ctx, accountId, pageState, pageSize)
+}
+
+func (r *timeoutRepository) ReadAllAccountIds(ctx context.Context) ([]gocql.UUID, error) {
+	ctx, cancel := withOptionalTimeout(ctx, r.timeouts.Read)
+	defer cancel()
+	return r.repo.ReadAllAccountIds(ctx)
+}
+
+type customerTimeoutRepository struct {
+	repo     CustomerEventRepository
+	timeouts OperationTimeouts
+}
+
+func withCustomerTimeouts(repo CustomerEventRepository, timeouts OperationTimeouts) CustomerEventRepository {
+	return &customerTimeoutRepository{
+		repo:     repo,
+		timeouts: timeouts,
+	}
+}
+
+func (r *customerTimeoutRepository) Write(ctx context.Context, events ...CustomerEvent) error {
+	ctx, cancel := withOptionalTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	return r.repo.Write(ctx, events...)
+}
+
+func (r *customerTimeoutRepository) ReadCustomerEvents(ctx context.Context, customerId gocql.UUID) ([]CustomerEvent, error) {
+	ctx, cancel := withOptionalTimeout(ctx, r.timeouts.Read)
+	defer cancel()
+	return r.repo.ReadCustomerEvents(ctx, customerId)
+}
+
+func (r *customerTimeoutRepository) ReadAllCustomerIds(ctx context.Context) ([]gocql.UUID, error) {
+	ctx, cancel := withOptionalTimeout(ctx, r.timeouts.Read)
+	defer cancel()
+	return r.repo.ReadAllCustomerIds(ctx)
+}
+
+type numberTimeoutRepository struct {
+	repo     AccountNumberRepository
+	timeouts OperationTimeouts
+}
+
+func withNumberTimeouts(repo AccountNumberRepository, timeouts OperationTimeouts) AccountNumberRepository {
+	if repo == nil {
+		return nil
+	}
+	return &numberTimeoutRepository{
+		repo:     repo,
+		timeouts: timeouts,
+	}
+}
+
+func (r *numberTimeoutRepository) Register(ctx context.Context, number AccountNumber, accountId gocql.UUID) (bool, error) {
+	ctx, cancel := withOptionalTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	return r.repo.Register(ctx, number, accountId)
+}
+
+func (r *numberTimeoutRepository) Lookup(ctx context.Context, number AccountNumber) (gocql.UUID, bool, error) {
+	ctx, cancel := withOptionalTimeout(ctx, r.timeouts.Read)
+	defer cancel()
+	return r.repo.Lookup(ctx, number)
+}
+
+func (r *numberTimeoutRepository) Release(ctx context.Context, number AccountNumber, accountId gocql.UUID) error {
+	ctx, cancel := withOptionalTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	return r.repo.Release(ctx, number, accountId)
+}
+
+func withOptionalTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
 }
@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/gocql/gocql"
+)
+
+const reversalWindow = 90 * 24 * time.Hour
+
+type bookedTransaction struct {
+	command TransactionCommand
+	amount  float64
+}
+
+func (a *Account) ReverseTransaction(ctx context.Context, eventId gocql.UUID, reason string) error {
+	if caller := CallerFrom(ctx); caller.IsCustomer() {
+		return NewPermissionDeniedError("%s can not reverse transactions", caller.Name)
+	}
+	if err := a.requireState("reverse a transaction", AccountActive, AccountFrozen); err != nil {
+		return err
+	}
+	if reversedBy, ok := a.ReversedBy(eventId); ok {
+		return NewDomainError("transaction %s was already reversed by %s", eventId, reversedBy)
+	}
+	transaction, ok := a.transactions[eventId]
+	if !ok || eventId.Time().Before(a.clock.Now().Add(-reversalWindow)) {
+		return NewDomainError("event %s is not a deposit or withdrawal of account %s within the last %d days", eventId, a.accountId, int(reversalWindow.Hours()/24))
+	}
+	if transaction.command == DepositCommand && a.AvailableBalance()-transaction.amount < a.limit {
+		return NewDomainError("reversing the deposit of %f would exceed the limit", transaction.amount)
+	}
+	return a.emit(ctx, TransactionReversedEvent{
+		AccountId:       a.accountId,
+		EventId:         gocql.TimeUUID(),
+		ReversedEventId: eventId,
+		Command:         transaction.command,
+		Amount:          transaction.amount,
+		Reason:          reason,
+	})
+}
+
+func (a *Account) ReversedBy(eventId gocql.UUID) (gocql.UUID, bool) {
+	reversedBy, ok := a.reversals[eventId]
+	return reversedBy, ok
+}
+
+func (a *Account) bookTransaction(eventId gocql.UUID, command TransactionCommand, amount float64) {
+	a.book(eventId, command, amount)
+	if a.transactions == nil {
+		a.transactions = map[gocql.UUID]bookedTransaction{}
+	}
+	a.transactions[eventId] = bookedTransaction{command: command, amount: amount}
+	a.transactionOrder = append(a.transactionOrder, eventId)
+	a.expireTransactions(eventId.Time())
+}
+
+func (a *Account) bookSettledTransaction(eventId gocql.UUID, command TransactionCommand, amount float64) {
+	a.book(eventId, command, amount)
+}
+
+func (a *Account) book(eventId gocql.UUID, command TransactionCommand, amount float64) {
+	a.recordTransaction(eventId, amount, command == WithdrawCommand)
+	if command == WithdrawCommand {
+		a.countWithdrawal(eventId.Time())
+	}
+}
+
+func (a *Account) expireTransactions(at time.Time) {
+	expired := 0
+	for _, eventId := range a.transactionOrder {
+		if eventId.Time().After(at.Add(-reversalWindow)) {
+			break
+		}
+		delete(a.transactions, eventId)
+		expired++
+	}
+	a.transactionOrder = a.transactionOrder[expired:]
+}
+
+func (a *Account) reverse(eventId, reversedBy gocql.UUID) {
+	if a.reversals == nil {
+		a.reversals = map[gocql.UUID]gocql.UUID{}
+	}
+	a.reversals[eventId] = reversedBy
+	delete(a.transactions, eventId)
+	a.forgetTransaction(eventId)
+}
@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gocql/gocql"
+)
+
+func TestReverseTransactionWithinWindow(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		bookedAt   time.Time
+		reversible bool
+	}{
+		{"booked today", now.Add(-time.Hour), true},
+		{"booked at the end of the window", now.Add(-reversalWindow + time.Hour), true},
+		{"booked before the window", now.Add(-reversalWindow - time.Hour), false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			acc := testAccount(now)
+			deposit := MoneyDipositedEvent{AccountId: acc.accountId, EventId: gocql.UUIDFromTime(test.bookedAt), Amount: 100, Currency: DefaultCurrency}
+			if err := acc.apply(deposit); err != nil {
+				t.Fatal(err)
+			}
+			err := acc.ReverseTransaction(context.Background(), deposit.EventId, "mistake")
+			if test.reversible != (err == nil) {
+				t.Fatalf("expected reversible %t, got %v", test.reversible, err)
+			}
+			if _, reversed := acc.ReversedBy(deposit.EventId); reversed != test.reversible {
+				t.Fatalf("expected reversed %t", test.reversible)
+			}
+		})
+	}
+}
+
+func TestReversibleTransactionsExpireAfterWindow(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	acc := testAccount(now)
+	old := MoneyDipositedEvent{AccountId: acc.accountId, EventId: gocql.UUIDFromTime(now.Add(-reversalWindow - 2*time.Hour)), Amount: 50, Currency: DefaultCurrency}
+	reversal := TransactionReversedEvent{AccountId: acc.accountId, EventId: gocql.UUIDFromTime(now.Add(-reversalWindow - time.Hour)), ReversedEventId: old.EventId, Command: DepositCommand, Amount: 50}
+	recent := MoneyDipositedEvent{AccountId: acc.accountId, EventId: gocql.UUIDFromTime(now.Add(-time.Hour)), Amount: 20, Currency: DefaultCurrency}
+	for _, event := range []AccountEvent{old, reversal, recent} {
+		if err := acc.apply(event); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(acc.transactions) != 1 || len(acc.transactionOrder) != 1 {
+		t.Fatalf("expected only the recent transaction to be kept, got %d transactions", len(acc.transactions))
+	}
+	if reversedBy, ok := acc.ReversedBy(old.EventId); !ok || reversedBy != reversal.EventId {
+		t.Fatalf("expected the expired transaction to stay reversed by %s", reversal.EventId)
+	}
+	if err := acc.ReverseTransaction(context.Background(), old.EventId, "again"); err == nil {
+		t.Fatal("expected a reversed transaction to not be reversible again")
+	}
+	if acc.Balance() != 20 {
+		t.Fatalf("expected a balance of 20, got %f", acc.Balance())
+	}
+}
@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"context"
+
+	"github.com/gocql/gocql"
+)
+
+type RuleOutcome string
+
+const (
+	RuleAllow  RuleOutcome = "allow"
+	RuleDeny   RuleOutcome = "deny"
+	RuleReview RuleOutcome = "review"
+)
+
+type TransactionCommand string
+
+const (
+	DepositCommand  TransactionCommand = "deposit"
+	WithdrawCommand TransactionCommand = "withdraw"
+)
+
+type TransactionCheck struct {
+	Command TransactionCommand
+	Amount  float64
+	Account *Account
+}
+
+type RuleDecision struct {
+	Outcome     RuleOutcome
+	Rule        string
+	RuleVersion int
+	Evaluated   int
+}
+
+type TransactionRules interface {
+	Evaluate(ctx context.Context, check TransactionCheck) (RuleDecision, error)
+}
+
+func (a *Account) checkRules(ctx context.Context, command TransactionCommand, amount float64) (*RuleDecisionRecordedEvent, error) {
+	if a.rules == nil {
+		return nil, nil
+	}
+	decision, err := a.rules.Evaluate(ctx, TransactionCheck{
+		Command: command,
+		Amount:  amount,
+		Account: a,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if decision.Evaluated == 0 {
+		return nil, nil
+	}
+	e := RuleDecisionRecordedEvent{
+		AccountId:   a.accountId,
+		EventId:     gocql.TimeUUID(),
+		Command:     command,
+		Amount:      amount,
+		Outcome:     decision.Outcome,
+		Rule:        decision.Rule,
+		RuleVersion: decision.RuleVersion,
+	}
+	if decision.Outcome == RuleAllow {
+		return &e, nil
+	}
+	if err := a.emit(ctx, e); err != nil {
+		return nil, err
+	}
+	return nil, NewRuleDecisionError(decision.Outcome, "%s of %f is %s by rule %s version %d", command, amount, decisionVerb(decision.Outcome), decision.Rule, decision.RuleVersion)
+}
+
+func decisionVerb(outcome RuleOutcome) string {
+	if outcome == RuleReview {
+		return "held for review"
+	}
+	return "denied"
+}
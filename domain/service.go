@@ -1,71 +1,310 @@
 package domain
 
 import (
+	"context"
+	"errors"
+	"log"
+
 	"github.com/gocql/gocql"
 )
 
 type AccountService struct {
-	repo AccountEventRepository
+	repo               AccountEventRepository
+	clock              Clock
+	rules              TransactionRules
+	approval           ApprovalPolicy
+	rates              ExchangeRates
+	fees               FeeSchedules
+	customers          *CustomerService
+	numbers            AccountNumberRepository
+	numbering          AccountNumbering
+	standingOrderRetry StandingOrderRetry
+	directDebit        DirectDebitPolicy
+}
+
+type AccountServiceConfig struct {
+	Timeouts           OperationTimeouts
+	Clock              Clock
+	Rules              TransactionRules
+	Approval           ApprovalPolicy
+	Rates              ExchangeRates
+	Fees               FeeSchedules
+	Customers          *CustomerService
+	Numbers            AccountNumberRepository
+	Numbering          AccountNumbering
+	StandingOrderRetry StandingOrderRetry
+	DirectDebit        DirectDebitPolicy
 }
 
-func NewAccountService(repo AccountEventRepository) AccountService {
+func NewAccountService(repo AccountEventRepository, config AccountServiceConfig) AccountService {
+	clock := config.Clock
+	if clock == nil {
+		clock = SystemClock{}
+	}
+	numbering := config.Numbering
+	if numbering == (AccountNumbering{}) {
+		numbering = DefaultAccountNumbering
+	}
+	directDebit := config.DirectDebit
+	if directDebit.RefundDays == 0 {
+		directDebit.RefundDays = DefaultDirectDebitRefundDays
+	}
 	return AccountService{
-		repo: repo,
+		repo:               withTimeouts(repo, config.Timeouts),
+		clock:              clock,
+		rules:              config.Rules,
+		approval:           config.Approval,
+		rates:              config.Rates,
+		fees:               config.Fees,
+		customers:          config.Customers,
+		numbers:            withNumberTimeouts(config.Numbers, config.Timeouts),
+		numbering:          numbering,
+		standingOrderRetry: config.StandingOrderRetry,
+		directDebit:        directDebit,
 	}
 }
 
-func (s *AccountService) CreateNewAccount() (Account, error) {
-	e := AccountCreatedEvent{
-		AccountId: gocql.MustRandomUUID(),
-		EventId:   gocql.TimeUUID(),
+type NewAccount struct {
+	CustomerId     gocql.UUID
+	Type           AccountType
+	Currency       Currency
+	InitialDeposit float64
+	Limit          float64
+	Pending        bool
+}
+
+func (s *AccountService) CreateNewAccount(ctx context.Context, newAccount NewAccount) (Account, error) {
+	if newAccount.InitialDeposit < 0 {
+		return Account{}, NewDomainError("a negative amount %f can not be diposited", newAccount.InitialDeposit)
+	}
+	if newAccount.Limit > 0 {
+		return Account{}, NewDomainError("new limit %f can not be positive", newAccount.Limit)
+	}
+	if newAccount.Pending && newAccount.InitialDeposit > 0 {
+		return Account{}, NewDomainError("a pending account can not be opened with an initial deposit")
+	}
+	if newAccount.Currency == "" {
+		newAccount.Currency = DefaultCurrency
+	}
+	currency, err := ParseCurrency(string(newAccount.Currency))
+	if err != nil {
+		return Account{}, err
+	}
+	if err := currency.checkAmount(newAccount.InitialDeposit); err != nil {
+		return Account{}, err
+	}
+	if err := currency.checkAmount(newAccount.Limit); err != nil {
+		return Account{}, err
+	}
+	accountType, err := ParseAccountType(string(newAccount.Type))
+	if err != nil {
+		return Account{}, err
+	}
+	if err := accountType.checkLimit(newAccount.Limit); err != nil {
+		return Account{}, err
+	}
+	if caller := CallerFrom(ctx); caller.IsCustomer() && caller.CustomerId != newAccount.CustomerId {
+		return Account{}, NewPermissionDeniedError("%s can not open accounts for customer %s", caller.Name, newAccount.CustomerId)
+	}
+	customer, err := s.accountCustomer(ctx, newAccount.CustomerId)
+	if err != nil {
+		return Account{}, err
 	}
-	if err := s.repo.Write(e); err != nil {
+	acc := s.newAccount(gocql.MustRandomUUID())
+	if err := customer.LinkAccount(ctx, acc.accountId); err != nil {
 		return Account{}, err
 	}
-	return s.GetAccount(e.AccountId)
+	number, err := s.assignAccountNumber(ctx, acc.accountId)
+	if err != nil {
+		return Account{}, errors.Join(err, customer.UnlinkAccount(ctx, acc.accountId))
+	}
+	events := []AccountEvent{
+		AccountCreatedEvent{
+			AccountId:     acc.accountId,
+			EventId:       gocql.TimeUUID(),
+			Pending:       newAccount.Pending,
+			Currency:      currency,
+			Type:          accountType,
+			CustomerId:    customer.customerId,
+			AccountNumber: number,
+		},
+	}
+	if policies := accountType.Profile().DefaultPolicies; policies != (LimitPolicies{}) {
+		events = append(events, LimitPoliciesSetEvent{
+			AccountId: acc.accountId,
+			EventId:   gocql.TimeUUID(),
+			Policies:  policies,
+		})
+	}
+	if newAccount.Limit != 0 {
+		events = append(events, LimitSetEvent{
+			AccountId: acc.accountId,
+			EventId:   gocql.TimeUUID(),
+			Limit:     newAccount.Limit,
+		})
+	}
+	if newAccount.InitialDeposit > 0 {
+		events = append(events, MoneyDipositedEvent{
+			AccountId: acc.accountId,
+			EventId:   gocql.TimeUUID(),
+			Amount:    newAccount.InitialDeposit,
+			Currency:  currency,
+		})
+	}
+	if err := acc.emit(ctx, events...); err != nil {
+		return Account{}, errors.Join(err, s.releaseAccountNumber(ctx, number, acc.accountId), customer.UnlinkAccount(ctx, acc.accountId))
+	}
+	return acc, nil
 }
 
-func (s *AccountService) GetAccount(accountId gocql.UUID) (Account, error) {
-	acc := Account{
-		repo:      s.repo,
-		accountId: accountId,
-		deleted:   false,
+func (s *AccountService) GetAccount(ctx context.Context, accountId gocql.UUID) (Account, error) {
+	acc, err := s.LoadAccount(ctx, accountId)
+	if err != nil {
+		return Account{}, err
 	}
-	events, err := s.repo.ReadAllEvents(accountId)
+	if acc.Deleted() {
+		return Account{}, NewAccountNotFoundError("account %s does not exist or is deleted", accountId)
+	}
+	return acc, nil
+}
+
+func (s *AccountService) LoadAccount(ctx context.Context, accountId gocql.UUID) (Account, error) {
+	acc := s.newAccount(accountId)
+	found := false
+	err := ForEachEvent(ctx, s.repo, accountId, DefaultPageSize, func(event AccountEvent) error {
+		found = true
+		return acc.apply(event)
+	})
 	if err != nil {
 		return acc, err
 	}
-	for _, event := range events {
-		err := event.Apply(&acc)
+	if !found {
+		return Account{}, NewAccountNotFoundError("account %s does not exist", accountId)
+	}
+	return acc, nil
+}
+
+func (s *AccountService) GetAccountEvents(ctx context.Context, accountId gocql.UUID, pageState []byte, pageSize int) (AccountEventPage, error) {
+	if pageSize <= 0 {
+		return AccountEventPage{}, NewDomainError("page size %d must be positive", pageSize)
+	}
+	page, err := s.repo.ReadEvents(ctx, accountId, pageState, pageSize)
+	if err != nil {
+		return page, err
+	}
+	if len(pageState) == 0 && len(page.Events) == 0 {
+		return page, NewAccountNotFoundError("account %s does not exist", accountId)
+	}
+	return page, nil
+}
+
+func (s *AccountService) GetAllAccountIds(ctx context.Context) ([]gocql.UUID, error) {
+	return s.findAccountIds(ctx, func(acc *Account) bool {
+		return !acc.Deleted()
+	})
+}
+
+func (s *AccountService) GetAccountIdsFor(ctx context.Context) ([]gocql.UUID, error) {
+	caller := CallerFrom(ctx)
+	return s.findAccountIds(ctx, func(acc *Account) bool {
+		return !acc.Deleted() && acc.authorize(caller, ViewPermission, 0) == nil
+	})
+}
+
+func (s *AccountService) GetDeletedAccountIds(ctx context.Context) ([]gocql.UUID, error) {
+	return s.findAccountIds(ctx, func(acc *Account) bool {
+		return acc.Deleted()
+	})
+}
+
+func (s *AccountService) ReopenAccount(ctx context.Context, accountId gocql.UUID) (Account, error) {
+	acc, err := s.LoadAccount(ctx, accountId)
+	if err != nil {
+		return acc, err
+	}
+	if err := acc.Reopen(ctx); err != nil {
+		return acc, err
+	}
+	return acc, nil
+}
+
+func (s *AccountService) ExpireHolds(ctx context.Context) (int, error) {
+	return s.forEachAccount(ctx, "expiring holds", func(acc *Account) (int, error) {
+		return acc.ExpireHolds(ctx)
+	})
+}
+
+func (s *AccountService) ExpireApprovals(ctx context.Context) (int, error) {
+	return s.forEachAccount(ctx, "expiring approvals", func(acc *Account) (int, error) {
+		return acc.ExpireApprovals(ctx)
+	})
+}
+
+func (s *AccountService) AccrueInterest(ctx context.Context) (int, error) {
+	return s.forEachAccount(ctx, "accruing interest", func(acc *Account) (int, error) {
+		return acc.AccrueInterest(ctx)
+	})
+}
+
+func (s *AccountService) forEachAccount(ctx context.Context, job string, step func(acc *Account) (int, error)) (int, error) {
+	ids, err := s.repo.ReadAllAccountIds(ctx)
+	if err != nil {
+		return 0, err
+	}
+	total := 0
+	for _, id := range ids {
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+		acc, err := s.LoadAccount(ctx, id)
 		if err != nil {
-			return acc, err
+			if _, ok := err.(*AccountNotFoundError); !ok {
+				log.Printf("Loading account %s failed while %s: %v", id, job, err)
+			}
+			continue
+		}
+		if acc.Deleted() {
+			continue
+		}
+		count, err := step(&acc)
+		total += count
+		if err != nil {
+			log.Printf("Account %s failed while %s: %v", id, job, err)
 		}
 	}
-	if len(events) == 0 || acc.deleted {
-		return Account{}, NewAccountNotFoundError("account %s does not exist or is deleted", accountId)
+	return total, nil
+}
+
+func (s *AccountService) newAccount(accountId gocql.UUID) Account {
+	return Account{
+		repo:      s.repo,
+		clock:     s.clock,
+		rules:     s.rules,
+		approval:  s.approval,
+		fees:      s.fees,
+		accountId: accountId,
 	}
-	return acc, nil
 }
 
-func (s *AccountService) GetAllAccountIds() ([]gocql.UUID, error) {
-	activeIds := []gocql.UUID{}
-	loadedIds, err := s.repo.ReadAllAccountIds()
+func (s *AccountService) findAccountIds(ctx context.Context, matches func(acc *Account) bool) ([]gocql.UUID, error) {
+	foundIds := []gocql.UUID{}
+	loadedIds, err := s.repo.ReadAllAccountIds(ctx)
 	if err != nil {
-		return activeIds, err
+		return foundIds, err
 	}
 	for _, id := range loadedIds {
-		acc, err := s.GetAccount(id)
+		acc, err := s.LoadAccount(ctx, id)
 		if err != nil {
 			switch err.(type) {
 			case *AccountNotFoundError:
 				continue
 			default:
-				return activeIds, err
+				return foundIds, err
 			}
 		}
-		if !acc.Deleted() {
-			activeIds = append(activeIds, id)
+		if matches(&acc) {
+			foundIds = append(foundIds, id)
 		}
 	}
-	return activeIds, nil
+	return foundIds, nil
 }
@@ -0,0 +1,393 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/gocql/gocql"
+)
+
+type StandingOrderInterval string
+
+const (
+	WeeklyInterval    StandingOrderInterval = "weekly"
+	MonthlyInterval   StandingOrderInterval = "monthly"
+	QuarterlyInterval StandingOrderInterval = "quarterly"
+	YearlyInterval    StandingOrderInterval = "yearly"
+)
+
+var intervalMonths = map[StandingOrderInterval]int{
+	MonthlyInterval:   1,
+	QuarterlyInterval: 3,
+	YearlyInterval:    12,
+}
+
+func ParseStandingOrderInterval(value string) (StandingOrderInterval, error) {
+	interval := StandingOrderInterval(value)
+	if _, ok := intervalMonths[interval]; ok || interval == WeeklyInterval {
+		return interval, nil
+	}
+	return "", NewDomainError("%s is not a valid interval, use %s, %s, %s or %s", value, WeeklyInterval, MonthlyInterval, QuarterlyInterval, YearlyInterval)
+}
+
+func (i StandingOrderInterval) next(start, due time.Time) time.Time {
+	months, ok := intervalMonths[i]
+	if !ok {
+		return due.AddDate(0, 0, 7)
+	}
+	first := time.Date(due.Year(), due.Month()+time.Month(months), 1, 0, 0, 0, 0, time.UTC)
+	dayOfMonth := start.Day()
+	if last := endOfMonth(first).Day(); dayOfMonth > last {
+		dayOfMonth = last
+	}
+	return time.Date(first.Year(), first.Month(), dayOfMonth, 0, 0, 0, 0, time.UTC)
+}
+
+type StandingOrderRetry struct {
+	MaxAttempts int
+	Delay       time.Duration
+}
+
+type StandingOrderTerms struct {
+	ToAccountId gocql.UUID
+	Amount      float64
+	Interval    StandingOrderInterval
+	StartDate   time.Time
+	EndDate     time.Time
+	Details     TransactionDetails
+}
+
+type StandingOrder struct {
+	OrderId       gocql.UUID
+	ToAccountId   gocql.UUID
+	Amount        float64
+	Interval      StandingOrderInterval
+	StartDate     time.Time
+	EndDate       time.Time
+	Details       TransactionDetails
+	NextExecution time.Time
+	Attempts      int
+	RetryAt       time.Time
+}
+
+func (o StandingOrder) IsTransfer() bool {
+	return o.ToAccountId != (gocql.UUID{})
+}
+
+func (o StandingOrder) DueAt() time.Time {
+	if !o.RetryAt.IsZero() {
+		return o.RetryAt
+	}
+	return o.NextExecution
+}
+
+func (o StandingOrder) following() time.Time {
+	next := o.Interval.next(o.StartDate, o.NextExecution)
+	if !o.EndDate.IsZero() && next.After(o.EndDate) {
+		return time.Time{}
+	}
+	return next
+}
+
+func (a *Account) StandingOrders() []StandingOrder {
+	orders := make([]StandingOrder, 0, len(a.standingOrders))
+	for _, order := range a.standingOrders {
+		orders = append(orders, order)
+	}
+	sort.Slice(orders, func(i, j int) bool {
+		return orders[i].DueAt().Before(orders[j].DueAt())
+	})
+	return orders
+}
+
+func (a *Account) StandingOrder(orderId gocql.UUID) (StandingOrder, error) {
+	order, ok := a.standingOrders[orderId]
+	if !ok {
+		return order, NewDomainError("standing order %s does not exist on account %s", orderId, a.accountId)
+	}
+	return order, nil
+}
+
+func (a *Account) checkStandingOrder(amount float64, interval StandingOrderInterval, end time.Time, details TransactionDetails) error {
+	if err := a.requireState("manage standing orders", AccountActive); err != nil {
+		return err
+	}
+	if a.accountType.Profile().WithdrawalApproval {
+		return NewDomainError("standing orders can not be set up on %s accounts", a.accountType)
+	}
+	if amount <= 0 {
+		return NewDomainError("the amount %f of a standing order must be positive", amount)
+	}
+	if err := a.currency.checkAmount(amount); err != nil {
+		return err
+	}
+	if a.approval.WithdrawalThreshold > 0 && amount > a.approval.WithdrawalThreshold {
+		return NewDomainError("the amount %f of a standing order can not exceed the approval threshold %f", amount, a.approval.WithdrawalThreshold)
+	}
+	if _, err := ParseStandingOrderInterval(string(interval)); err != nil {
+		return err
+	}
+	return details.validate()
+}
+
+func (a *Account) checkStandingOrderDates(start, end time.Time) error {
+	if start.Before(startOfDay(a.clock.Now())) {
+		return NewDomainError("a standing order can not be executed in the past on %s", start.Format(time.DateOnly))
+	}
+	if !end.IsZero() && end.Before(start) {
+		return NewDomainError("a standing order can not end on %s before its execution on %s", end.Format(time.DateOnly), start.Format(time.DateOnly))
+	}
+	return nil
+}
+
+func (a *Account) createStandingOrder(ctx context.Context, terms StandingOrderTerms) (StandingOrder, error) {
+	if terms.StartDate.IsZero() {
+		terms.StartDate = a.clock.Now()
+	}
+	terms.StartDate = startOfDay(terms.StartDate)
+	if !terms.EndDate.IsZero() {
+		terms.EndDate = startOfDay(terms.EndDate)
+	}
+	if err := a.checkStandingOrder(terms.Amount, terms.Interval, terms.EndDate, terms.Details); err != nil {
+		return StandingOrder{}, err
+	}
+	if err := a.checkStandingOrderDates(terms.StartDate, terms.EndDate); err != nil {
+		return StandingOrder{}, err
+	}
+	e := StandingOrderCreatedEvent{
+		AccountId:   a.accountId,
+		EventId:     gocql.TimeUUID(),
+		OrderId:     gocql.MustRandomUUID(),
+		ToAccountId: terms.ToAccountId,
+		Amount:      terms.Amount,
+		Interval:    terms.Interval,
+		StartDate:   terms.StartDate,
+		EndDate:     terms.EndDate,
+		Details:     terms.Details,
+	}
+	if err := a.emit(ctx, e); err != nil {
+		return StandingOrder{}, err
+	}
+	return a.standingOrders[e.OrderId], nil
+}
+
+func (a *Account) AmendStandingOrder(ctx context.Context, orderId gocql.UUID, terms StandingOrderTerms) error {
+	order, err := a.StandingOrder(orderId)
+	if err != nil {
+		return err
+	}
+	if terms.ToAccountId != (gocql.UUID{}) && terms.ToAccountId != order.ToAccountId {
+		return NewDomainError("the recipient of standing order %s can not be changed", orderId)
+	}
+	if order.IsTransfer() && terms.Details.CounterpartyAccount == "" {
+		terms.Details.CounterpartyAccount = order.Details.CounterpartyAccount
+	}
+	if !terms.EndDate.IsZero() {
+		terms.EndDate = startOfDay(terms.EndDate)
+	}
+	if err := a.checkStandingOrder(terms.Amount, terms.Interval, terms.EndDate, terms.Details); err != nil {
+		return err
+	}
+	if terms.StartDate.IsZero() {
+		terms.StartDate = order.NextExecution
+		if !terms.EndDate.IsZero() && terms.EndDate.Before(terms.StartDate) {
+			return NewDomainError("a standing order can not end on %s before its execution on %s", terms.EndDate.Format(time.DateOnly), terms.StartDate.Format(time.DateOnly))
+		}
+	} else {
+		terms.StartDate = startOfDay(terms.StartDate)
+		if err := a.checkStandingOrderDates(terms.StartDate, terms.EndDate); err != nil {
+			return err
+		}
+	}
+	return a.emit(ctx, StandingOrderAmendedEvent{
+		AccountId:     a.accountId,
+		EventId:       gocql.TimeUUID(),
+		OrderId:       orderId,
+		Amount:        terms.Amount,
+		Interval:      terms.Interval,
+		NextExecution: terms.StartDate,
+		EndDate:       terms.EndDate,
+		Details:       terms.Details,
+	})
+}
+
+func (a *Account) CancelStandingOrder(ctx context.Context, orderId gocql.UUID, reason string) error {
+	if _, err := a.StandingOrder(orderId); err != nil {
+		return err
+	}
+	return a.emit(ctx, StandingOrderCancelledEvent{
+		AccountId: a.accountId,
+		EventId:   gocql.TimeUUID(),
+		OrderId:   orderId,
+		Reason:    reason,
+	})
+}
+
+func (a *Account) executeStandingOrder(ctx context.Context, order StandingOrder, retry StandingOrderRetry) (*MoneyWithdrawnEvent, error) {
+	_, fees := a.withdrawalFees(order.Amount)
+	if a.state == AccountActive && a.AvailableBalance()-order.Amount-fees < a.limit {
+		cause := NewDomainError("insufficient funds for %f", order.Amount)
+		return nil, a.failStandingOrder(ctx, order, cause, retry)
+	}
+	if err := a.checkWithdrawal(order.Amount); err != nil {
+		return nil, a.failStandingOrder(ctx, order, err, StandingOrderRetry{})
+	}
+	decision, err := a.checkRules(ctx, WithdrawCommand, order.Amount)
+	if err != nil {
+		return nil, a.failStandingOrder(ctx, order, err, StandingOrderRetry{})
+	}
+	withdrawal := MoneyWithdrawnEvent{
+		AccountId: a.accountId,
+		EventId:   gocql.TimeUUID(),
+		Amount:    order.Amount,
+		Details:   order.Details,
+		Currency:  a.currency,
+	}
+	events := withDecision(decision, withdrawal)
+	feeEvents, _ := a.withdrawalFees(order.Amount)
+	events = append(events, feeEvents...)
+	events = append(events, StandingOrderExecutedEvent{
+		AccountId:     a.accountId,
+		EventId:       gocql.TimeUUID(),
+		OrderId:       order.OrderId,
+		DueDate:       order.NextExecution,
+		Amount:        order.Amount,
+		TransactionId: withdrawal.EventId,
+		NextExecution: order.following(),
+	})
+	if err := a.emit(ctx, events...); err != nil {
+		return nil, err
+	}
+	return &withdrawal, nil
+}
+
+func (a *Account) failStandingOrder(ctx context.Context, order StandingOrder, cause error, retry StandingOrderRetry) error {
+	switch cause.(type) {
+	case *DomainError, *RuleDecisionError:
+	default:
+		return cause
+	}
+	e := StandingOrderFailedEvent{
+		AccountId: a.accountId,
+		EventId:   gocql.TimeUUID(),
+		OrderId:   order.OrderId,
+		DueDate:   order.NextExecution,
+		Attempt:   order.Attempts + 1,
+		Reason:    cause.Error(),
+	}
+	if e.Attempt < retry.MaxAttempts {
+		e.RetryAt = a.clock.Now().Add(retry.Delay)
+	} else {
+		e.NextExecution = order.following()
+	}
+	return a.emit(ctx, e)
+}
+
+func (s *AccountService) CreateStandingOrder(ctx context.Context, accountId gocql.UUID, terms StandingOrderTerms) (StandingOrder, error) {
+	acc, err := s.AccountFor(ctx, accountId, FullPermission)
+	if err != nil {
+		return StandingOrder{}, err
+	}
+	if terms.ToAccountId != (gocql.UUID{}) {
+		if terms.ToAccountId == accountId {
+			return StandingOrder{}, NewDomainError("a standing order can not transfer to its own account %s", accountId)
+		}
+		to, err := s.GetAccount(ctx, terms.ToAccountId)
+		if err != nil {
+			return StandingOrder{}, err
+		}
+		if to.currency != acc.currency {
+			return StandingOrder{}, NewDomainError("accounts %s and %s are not held in the same currency", accountId, terms.ToAccountId)
+		}
+		if terms.Details.CounterpartyAccount == "" {
+			terms.Details.CounterpartyAccount = to.reference()
+		}
+	}
+	return acc.createStandingOrder(ctx, terms)
+}
+
+func (s *AccountService) ExecuteStandingOrders(ctx context.Context) (int, error) {
+	return s.forEachAccount(ctx, "executing standing orders", func(acc *Account) (int, error) {
+		processed := 0
+		now := s.clock.Now()
+		for _, order := range acc.StandingOrders() {
+			if order.DueAt().After(now) {
+				break
+			}
+			if err := s.executeStandingOrder(ctx, acc, order); err != nil {
+				return processed, err
+			}
+			processed++
+		}
+		return processed, nil
+	})
+}
+
+func (s *AccountService) executeStandingOrder(ctx context.Context, acc *Account, order StandingOrder) error {
+	if !order.IsTransfer() {
+		_, err := acc.executeStandingOrder(ctx, order, s.standingOrderRetry)
+		return err
+	}
+	to, err := s.GetAccount(ctx, order.ToAccountId)
+	if _, ok := err.(*AccountNotFoundError); ok {
+		return acc.failStandingOrder(ctx, order, NewDomainError("recipient account %s does not exist", order.ToAccountId), StandingOrderRetry{})
+	}
+	if err != nil {
+		return err
+	}
+	if err := to.requireState("receive a transfer", AccountActive, AccountFrozen); err != nil {
+		return acc.failStandingOrder(ctx, order, err, StandingOrderRetry{})
+	}
+	withdrawal, err := acc.executeStandingOrder(ctx, order, s.standingOrderRetry)
+	if err != nil || withdrawal == nil {
+		return err
+	}
+	err = to.emit(ctx, MoneyDipositedEvent{
+		AccountId: to.accountId,
+		EventId:   gocql.TimeUUID(),
+		Amount:    order.Amount,
+		Currency:  to.currency,
+		Details: TransactionDetails{
+			Reference:           order.Details.Reference,
+			Description:         order.Details.Description,
+			CounterpartyAccount: acc.reference(),
+			Category:            order.Details.Category,
+		},
+	})
+	if err != nil {
+		refund := acc.emit(ctx, TransactionReversedEvent{
+			AccountId:       acc.accountId,
+			EventId:         gocql.TimeUUID(),
+			ReversedEventId: withdrawal.EventId,
+			Command:         WithdrawCommand,
+			Amount:          order.Amount,
+			Reason:          "crediting the standing order transfer failed",
+		})
+		return errors.Join(err, refund)
+	}
+	return nil
+}
+
+func (a *Account) reference() string {
+	if a.accountNumber != "" {
+		return string(a.accountNumber)
+	}
+	return a.accountId.String()
+}
+
+func (a *Account) scheduleStandingOrder(orderId gocql.UUID, next time.Time) {
+	order, ok := a.standingOrders[orderId]
+	if !ok {
+		return
+	}
+	if next.IsZero() {
+		delete(a.standingOrders, orderId)
+		return
+	}
+	order.NextExecution = next
+	order.Attempts = 0
+	order.RetryAt = time.Time{}
+	a.standingOrders[orderId] = order
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/gocql/gocql"
+	"github.com/thomaszub/go-es-example/domain"
+)
+
+func printEvents(cfg Config, args []string) error {
+	flags := flag.NewFlagSet("events", flag.ExitOnError)
+	account := flags.String("account", "", "id of the account to print the events of")
+	pageSize := flags.Int("page-size", domain.DefaultPageSize, "number of events read per page")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *account == "" {
+		return errors.New("account is not set")
+	}
+	accountId, err := gocql.ParseUUID(*account)
+	if err != nil {
+		return fmt.Errorf("%s is not a valid id", *account)
+	}
+
+	b, err := openBackend(cfg, cfg.EventStore)
+	if err != nil {
+		return err
+	}
+	defer b.close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	return domain.ForEachEvent(ctx, b.accounts, accountId, *pageSize, func(event domain.AccountEvent) error {
+		_, err := fmt.Printf("%s\t%T\t%+v\n", event.GetEventId().Time().Format("2006-01-02T15:04:05.000Z07:00"), event, event)
+		return err
+	})
+}
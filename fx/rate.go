@@ -0,0 +1,28 @@
+package fx
+
+import (
+	"context"
+
+	"github.com/gocql/gocql"
+	"github.com/thomaszub/go-es-example/domain"
+)
+
+type RateSnapshot struct {
+	SnapshotId gocql.UUID      `json:"snapshotId"`
+	From       domain.Currency `json:"from"`
+	To         domain.Currency `json:"to"`
+	Rate       float64         `json:"rate"`
+	Spread     float64         `json:"spread"`
+}
+
+type RateSnapshotRepository interface {
+	Write(ctx context.Context, snapshot RateSnapshot) error
+	ReadAll(ctx context.Context) ([]RateSnapshot, error)
+}
+
+type RateDefinition struct {
+	From   string  `json:"from"`
+	To     string  `json:"to"`
+	Rate   float64 `json:"rate"`
+	Spread float64 `json:"spread"`
+}
@@ -0,0 +1,211 @@
+package fx
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gocql/gocql"
+	"github.com/thomaszub/go-es-example/domain"
+)
+
+type RateTable struct {
+	repo    RateSnapshotRepository
+	mu      sync.Mutex
+	current map[pair]RateSnapshot
+}
+
+func NewRateTable(repo RateSnapshotRepository) *RateTable {
+	return &RateTable{
+		repo: repo,
+	}
+}
+
+func (t *RateTable) Rates(ctx context.Context) ([]RateSnapshot, error) {
+	current, err := t.currentRates(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rates := make([]RateSnapshot, 0, len(current))
+	for _, snapshot := range current {
+		rates = append(rates, snapshot)
+	}
+	sort.Slice(rates, func(i, j int) bool {
+		if rates[i].From != rates[j].From {
+			return rates[i].From < rates[j].From
+		}
+		return rates[i].To < rates[j].To
+	})
+	return rates, nil
+}
+
+func (t *RateTable) Snapshot(ctx context.Context, snapshotId gocql.UUID) (RateSnapshot, error) {
+	snapshots, err := t.repo.ReadAll(ctx)
+	if err != nil {
+		return RateSnapshot{}, err
+	}
+	for _, snapshot := range snapshots {
+		if snapshot.SnapshotId == snapshotId {
+			return snapshot, nil
+		}
+	}
+	return RateSnapshot{}, domain.NewDomainError("rate snapshot %s does not exist", snapshotId)
+}
+
+func (t *RateTable) SetRate(ctx context.Context, definition RateDefinition) (RateSnapshot, error) {
+	from, err := domain.ParseCurrency(definition.From)
+	if err != nil {
+		return RateSnapshot{}, err
+	}
+	to, err := domain.ParseCurrency(definition.To)
+	if err != nil {
+		return RateSnapshot{}, err
+	}
+	if from == to {
+		return RateSnapshot{}, domain.NewDomainError("a rate needs two different currencies, got %s twice", from)
+	}
+	if definition.Rate <= 0 {
+		return RateSnapshot{}, domain.NewDomainError("rate %f must be positive", definition.Rate)
+	}
+	if definition.Spread < 0 || definition.Spread >= 1 {
+		return RateSnapshot{}, domain.NewDomainError("spread %f must be at least 0 and below 1", definition.Spread)
+	}
+	snapshot := RateSnapshot{
+		SnapshotId: gocql.TimeUUID(),
+		From:       from,
+		To:         to,
+		Rate:       definition.Rate,
+		Spread:     definition.Spread,
+	}
+	if err := t.repo.Write(ctx, snapshot); err != nil {
+		return RateSnapshot{}, err
+	}
+	t.invalidate()
+	return snapshot, nil
+}
+
+func (t *RateTable) Rate(ctx context.Context, from, to domain.Currency) (domain.ExchangeRate, error) {
+	current, err := t.currentRates(ctx)
+	if err != nil {
+		return domain.ExchangeRate{}, err
+	}
+	if snapshot, ok := current[pair{from, to}]; ok {
+		return domain.ExchangeRate{
+			SnapshotId: snapshot.SnapshotId,
+			From:       from,
+			To:         to,
+			Rate:       snapshot.Rate,
+			Spread:     snapshot.Spread,
+		}, nil
+	}
+	if snapshot, ok := current[pair{to, from}]; ok {
+		return domain.ExchangeRate{
+			SnapshotId: snapshot.SnapshotId,
+			From:       from,
+			To:         to,
+			Rate:       1 / snapshot.Rate,
+			Spread:     snapshot.Spread,
+		}, nil
+	}
+	return domain.ExchangeRate{}, domain.NewDomainError("there is no rate from %s to %s", from, to)
+}
+
+func LoadRateFile(ctx context.Context, table *RateTable, path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	definitions, lines, err := decodeRateFile(data)
+	if err != nil {
+		return 0, fmt.Errorf("rate file %s is invalid: %w", path, err)
+	}
+	current, err := table.currentRates(ctx)
+	if err != nil {
+		return 0, err
+	}
+	loaded := 0
+	for i, definition := range definitions {
+		from, err := domain.ParseCurrency(definition.From)
+		if err != nil {
+			return loaded, fmt.Errorf("rate file %s line %d: %w", path, lines[i], err)
+		}
+		to, err := domain.ParseCurrency(definition.To)
+		if err != nil {
+			return loaded, fmt.Errorf("rate file %s line %d: %w", path, lines[i], err)
+		}
+		existing, ok := current[pair{from, to}]
+		if ok && existing.Rate == definition.Rate && existing.Spread == definition.Spread {
+			continue
+		}
+		if _, err := table.SetRate(ctx, definition); err != nil {
+			return loaded, fmt.Errorf("rate file %s line %d: %w", path, lines[i], err)
+		}
+		loaded++
+	}
+	return loaded, nil
+}
+
+func decodeRateFile(data []byte) ([]RateDefinition, []int, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if token, err := decoder.Token(); err != nil || token != json.Delim('[') {
+		return nil, nil, errors.New("expected a list of rates")
+	}
+	var definitions []RateDefinition
+	var lines []int
+	for decoder.More() {
+		offset := int(decoder.InputOffset())
+		for offset < len(data) && strings.ContainsRune(" \t\r\n,", rune(data[offset])) {
+			offset++
+		}
+		line := 1 + bytes.Count(data[:offset], []byte("\n"))
+		var definition RateDefinition
+		if err := decoder.Decode(&definition); err != nil {
+			return nil, nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		definitions = append(definitions, definition)
+		lines = append(lines, line)
+	}
+	return definitions, lines, nil
+}
+
+type pair struct {
+	from domain.Currency
+	to   domain.Currency
+}
+
+func (t *RateTable) currentRates(ctx context.Context) (map[pair]RateSnapshot, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.current == nil {
+		current, err := t.loadCurrent(ctx)
+		if err != nil {
+			return nil, err
+		}
+		t.current = current
+	}
+	return t.current, nil
+}
+
+func (t *RateTable) invalidate() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.current = nil
+}
+
+func (t *RateTable) loadCurrent(ctx context.Context) (map[pair]RateSnapshot, error) {
+	snapshots, err := t.repo.ReadAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	current := map[pair]RateSnapshot{}
+	for _, snapshot := range snapshots {
+		current[pair{snapshot.From, snapshot.To}] = snapshot
+	}
+	return current, nil
+}
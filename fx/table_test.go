@@ -0,0 +1,124 @@
+package fx
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/thomaszub/go-es-example/domain"
+)
+
+type countingRateSnapshotRepository struct {
+	snapshots []RateSnapshot
+	reads     int
+}
+
+func (r *countingRateSnapshotRepository) Write(ctx context.Context, snapshot RateSnapshot) error {
+	r.snapshots = append(r.snapshots, snapshot)
+	return nil
+}
+
+func (r *countingRateSnapshotRepository) ReadAll(ctx context.Context) ([]RateSnapshot, error) {
+	r.reads++
+	return append([]RateSnapshot(nil), r.snapshots...), nil
+}
+
+func TestRateTableCachesUntilRateIsSet(t *testing.T) {
+	ctx := context.Background()
+	repo := &countingRateSnapshotRepository{}
+	table := NewRateTable(repo)
+	if _, err := table.SetRate(ctx, RateDefinition{From: "EUR", To: "USD", Rate: 1.1}); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := table.Rate(ctx, domain.Currency("EUR"), domain.Currency("USD")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if repo.reads != 1 {
+		t.Fatalf("expected one read for repeated rates, got %d", repo.reads)
+	}
+	if _, err := table.SetRate(ctx, RateDefinition{From: "EUR", To: "USD", Rate: 1.2}); err != nil {
+		t.Fatal(err)
+	}
+	rate, err := table.Rate(ctx, domain.Currency("USD"), domain.Currency("EUR"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rate.Rate != 1/1.2 {
+		t.Fatalf("expected the inverse of the new rate, got %f", rate.Rate)
+	}
+	if repo.reads != 2 {
+		t.Fatalf("expected setting a rate to invalidate the cache, got %d reads", repo.reads)
+	}
+}
+
+func TestLoadRateFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		loaded  int
+		err     string
+	}{
+		{
+			name:    "changed rates",
+			content: "[\n  {\"from\": \"EUR\", \"to\": \"USD\", \"rate\": 1.1},\n  {\"from\": \"EUR\", \"to\": \"GBP\", \"rate\": 0.85}\n]",
+			loaded:  2,
+		},
+		{
+			name:    "unknown currency",
+			content: "[\n  {\"from\": \"EUR\", \"to\": \"USD\", \"rate\": 1.1},\n  {\"from\": \"EUR\", \"to\": \"XXX\", \"rate\": 2}\n]",
+			loaded:  1,
+			err:     "line 3",
+		},
+		{
+			name:    "invalid rate",
+			content: "[\n  {\"from\": \"EUR\", \"to\": \"USD\", \"rate\": -1}\n]",
+			err:     "line 2",
+		},
+		{
+			name:    "malformed entry",
+			content: "[\n  {\"from\": \"EUR\", \"to\": \"USD\", \"rate\": 1.1},\n  {\"from\": 1}\n]",
+			err:     "line 3",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "rates.json")
+			if err := os.WriteFile(path, []byte(test.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			loaded, err := LoadRateFile(context.Background(), NewRateTable(&countingRateSnapshotRepository{}), path)
+			if test.err == "" && err != nil {
+				t.Fatal(err)
+			}
+			if test.err != "" && (err == nil || !strings.Contains(err.Error(), test.err)) {
+				t.Fatalf("expected an error mentioning %s, got %v", test.err, err)
+			}
+			if loaded != test.loaded {
+				t.Fatalf("expected %d loaded rates, got %d", test.loaded, loaded)
+			}
+		})
+	}
+}
+
+func TestLoadRateFileSkipsUnchangedRates(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "rates.json")
+	if err := os.WriteFile(path, []byte(`[{"from": "EUR", "to": "USD", "rate": 1.1}]`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	table := NewRateTable(&countingRateSnapshotRepository{})
+	if _, err := LoadRateFile(ctx, table, path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadRateFile(ctx, table, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded != 0 {
+		t.Fatalf("expected an unchanged rate not to be loaded again, got %d", loaded)
+	}
+}
@@ -3,6 +3,7 @@ module github.com/thomaszub/go-es-example
 go 1.20
 
 require (
+	github.com/expr-lang/expr v1.16.9
 	github.com/gocql/gocql v1.4.0
 	github.com/joho/godotenv v1.5.1
 	github.com/labstack/echo/v4 v4.10.2
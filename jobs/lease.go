@@ -0,0 +1,42 @@
+package jobs
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/thomaszub/go-es-example/domain"
+)
+
+type lease struct {
+	owner     string
+	expiresAt time.Time
+}
+
+type LocalLeaseStore struct {
+	mu     sync.Mutex
+	clock  domain.Clock
+	leases map[string]lease
+}
+
+func NewLocalLeaseStore(clock domain.Clock) *LocalLeaseStore {
+	return &LocalLeaseStore{
+		clock:  clock,
+		leases: map[string]lease{},
+	}
+}
+
+func (s *LocalLeaseStore) Acquire(ctx context.Context, job, owner string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.clock.Now()
+	current, ok := s.leases[job]
+	if ok && current.owner != owner && current.expiresAt.After(now) {
+		return false, nil
+	}
+	s.leases[job] = lease{
+		owner:     owner,
+		expiresAt: now.Add(ttl),
+	}
+	return true, nil
+}
@@ -0,0 +1,75 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+type LeaseStore interface {
+	Acquire(ctx context.Context, job, owner string, ttl time.Duration) (bool, error)
+}
+
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+}
+
+type Runner struct {
+	leases LeaseStore
+	owner  string
+	jobs   []Job
+	wg     sync.WaitGroup
+}
+
+func NewRunner(leases LeaseStore, owner string) *Runner {
+	return &Runner{
+		leases: leases,
+		owner:  owner,
+	}
+}
+
+func (r *Runner) Register(job Job) {
+	r.jobs = append(r.jobs, job)
+}
+
+func (r *Runner) Start(ctx context.Context) {
+	for _, job := range r.jobs {
+		r.wg.Add(1)
+		go r.schedule(ctx, job)
+	}
+}
+
+func (r *Runner) Wait() {
+	r.wg.Wait()
+}
+
+func (r *Runner) schedule(ctx context.Context, job Job) {
+	defer r.wg.Done()
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.runOnce(ctx, job)
+		}
+	}
+}
+
+func (r *Runner) runOnce(ctx context.Context, job Job) {
+	acquired, err := r.leases.Acquire(ctx, job.Name, r.owner, 2*job.Interval)
+	if err != nil {
+		log.Printf("Acquiring lease for job %s failed: %v", job.Name, err)
+		return
+	}
+	if !acquired {
+		return
+	}
+	if err := job.Run(ctx); err != nil {
+		log.Printf("Job %s failed: %v", job.Name, err)
+	}
+}
@@ -1,7 +1,12 @@
 package main
 
 import (
+	"context"
+	"io"
 	"log"
+	"net/http"
+	"os"
+	"os/signal"
 
 	"github.com/gocql/gocql"
 	"github.com/labstack/echo/v4"
@@ -9,6 +14,10 @@ import (
 	"github.com/thomaszub/go-es-example/api"
 	"github.com/thomaszub/go-es-example/database"
 	"github.com/thomaszub/go-es-example/domain"
+	"github.com/thomaszub/go-es-example/fx"
+	"github.com/thomaszub/go-es-example/jobs"
+	"github.com/thomaszub/go-es-example/migration"
+	"github.com/thomaszub/go-es-example/rules"
 )
 
 func main() {
@@ -17,27 +26,238 @@ func main() {
 		log.Fatal(err)
 	}
 
-	err = database.Initialize(cfg.CassandraCluster, cfg.CassandraKeyspace)
+	command := "serve"
+	if len(os.Args) > 1 {
+		command = os.Args[1]
+	}
+	switch command {
+	case "serve":
+		err = serve(cfg)
+	case "migrate":
+		err = migrate(cfg, os.Args[2:])
+	case "events":
+		err = printEvents(cfg, os.Args[2:])
+	default:
+		log.Fatalf("%s is not a known command", command)
+	}
 	if err != nil {
 		log.Fatal(err)
 	}
+}
 
-	cluster := gocql.NewCluster(cfg.CassandraCluster...)
-	cluster.Keyspace = cfg.CassandraKeyspace
-	session, err := cluster.CreateSession()
+func serve(cfg Config) error {
+	b, err := openBackend(cfg, cfg.EventStore)
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
-	defer session.Close()
+	defer b.close()
 
-	repo := database.InitRepository(session)
-	service := domain.NewAccountService(&repo)
+	engine := rules.NewEngine(b.rules)
+	rates := fx.NewRateTable(b.rates)
+	if cfg.FxRateFile != "" {
+		loaded, err := fx.LoadRateFile(context.Background(), rates, cfg.FxRateFile)
+		if err != nil {
+			return err
+		}
+		log.Printf("Loaded %d changed exchange rates from %s", loaded, cfg.FxRateFile)
+	}
+	timeouts := domain.OperationTimeouts{
+		Read:  cfg.ReadTimeout,
+		Write: cfg.WriteTimeout,
+	}
+	customers := domain.NewCustomerService(b.customers, timeouts)
+	service := domain.NewAccountService(b.accounts, domain.AccountServiceConfig{
+		Timeouts:           timeouts,
+		Rules:              engine,
+		Approval:           cfg.Approval,
+		Rates:              rates,
+		Fees:               cfg.Fees,
+		Customers:          &customers,
+		Numbers:            b.numbers,
+		Numbering:          cfg.Numbering,
+		StandingOrderRetry: cfg.StandingOrderRetry,
+		DirectDebit:        cfg.DirectDebit,
+	})
 	controller := api.NewAccountController(&service)
+	ruleController := api.NewRuleController(engine)
+	fxController := api.NewFxController(rates)
+	customerController := api.NewCustomerController(&customers, &service)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	runner := jobs.NewRunner(b.leases, cfg.InstanceId)
+	runner.Register(jobs.Job{
+		Name:     "expire-holds",
+		Interval: cfg.HoldExpiryInterval,
+		Run: func(ctx context.Context) error {
+			count, err := service.ExpireHolds(ctx)
+			if count > 0 {
+				log.Printf("Expired %d holds", count)
+			}
+			return err
+		},
+	})
+	runner.Register(jobs.Job{
+		Name:     "expire-approvals",
+		Interval: cfg.ApprovalExpiryInterval,
+		Run: func(ctx context.Context) error {
+			count, err := service.ExpireApprovals(ctx)
+			if count > 0 {
+				log.Printf("Expired %d approval requests", count)
+			}
+			return err
+		},
+	})
+	runner.Register(jobs.Job{
+		Name:     "accrue-interest",
+		Interval: cfg.InterestAccrualInterval,
+		Run: func(ctx context.Context) error {
+			count, err := service.AccrueInterest(ctx)
+			if count > 0 {
+				log.Printf("Recorded %d interest events", count)
+			}
+			return err
+		},
+	})
+	runner.Register(jobs.Job{
+		Name:     "execute-standing-orders",
+		Interval: cfg.StandingOrderInterval,
+		Run: func(ctx context.Context) error {
+			count, err := service.ExecuteStandingOrders(ctx)
+			if count > 0 {
+				log.Printf("Processed %d due standing orders", count)
+			}
+			return err
+		},
+	})
+	runner.Start(ctx)
+	defer func() {
+		stop()
+		runner.Wait()
+	}()
 
 	e := echo.New()
 	e.Use(middleware.Recover())
 	e.Use(middleware.Logger())
+	if len(cfg.Users) == 0 {
+		log.Println("API_USERS is not set, requests are not authenticated")
+	}
+	e.Use(api.Authenticate(cfg.Users))
 	g := e.Group("/api/accounts")
 	controller.RegisterOn(g)
-	e.Logger.Fatal(e.Start(":8000"))
+	admin := e.Group("/api/admin", api.RequireRole(cfg.Users, domain.AdminRole))
+	controller.RegisterAdminOn(admin.Group("/accounts"))
+	ruleController.RegisterOn(admin.Group("/rules"))
+	fxController.RegisterOn(admin.Group("/fx"))
+	customerController.RegisterOn(e.Group("/api/customers"))
+	go func() {
+		<-ctx.Done()
+		if err := e.Shutdown(context.Background()); err != nil {
+			log.Println(err)
+		}
+	}()
+	if err := e.Start(":8000"); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
+
+type backend struct {
+	accounts  domain.AccountEventRepository
+	customers domain.CustomerEventRepository
+	numbers   domain.AccountNumberRepository
+	leases    jobs.LeaseStore
+	rules     rules.RuleEventRepository
+	rates     fx.RateSnapshotRepository
+	close     func()
+}
+
+func (b backend) stores() migration.Stores {
+	return migration.Stores{
+		Accounts:  b.accounts,
+		Customers: b.customers,
+		Numbers:   b.numbers,
+		Rules:     b.rules,
+		Rates:     b.rates,
+	}
+}
+
+func openBackend(cfg Config, store string) (backend, error) {
+	switch store {
+	case fileEventStore:
+		options := database.SegmentLogOptions{
+			FsyncPolicy:   cfg.FileStoreFsync,
+			FsyncInterval: cfg.FileStoreFsyncInterval,
+		}
+		repo, err := database.OpenFileRepository(cfg.FileStoreDir, options)
+		if err != nil {
+			return backend{}, err
+		}
+		ruleRepo, err := database.OpenFileRuleRepository(cfg.FileStoreDir, options)
+		if err != nil {
+			closeAll(repo)
+			return backend{}, err
+		}
+		rateRepo, err := database.OpenFileRateSnapshotRepository(cfg.FileStoreDir, options)
+		if err != nil {
+			closeAll(repo, ruleRepo)
+			return backend{}, err
+		}
+		customerRepo, err := database.OpenFileCustomerRepository(cfg.FileStoreDir, options)
+		if err != nil {
+			closeAll(repo, ruleRepo, rateRepo)
+			return backend{}, err
+		}
+		numberRepo, err := database.OpenFileAccountNumberRepository(cfg.FileStoreDir, options)
+		if err != nil {
+			closeAll(repo, ruleRepo, rateRepo, customerRepo)
+			return backend{}, err
+		}
+		return backend{
+			accounts:  repo,
+			customers: customerRepo,
+			numbers:   numberRepo,
+			leases:    jobs.NewLocalLeaseStore(domain.SystemClock{}),
+			rules:     ruleRepo,
+			rates:     rateRepo,
+			close: func() {
+				closeAll(repo, ruleRepo, rateRepo, customerRepo, numberRepo)
+			},
+		}, nil
+	default:
+		err := database.Initialize(cfg.CassandraCluster, cfg.CassandraKeyspace)
+		if err != nil {
+			return backend{}, err
+		}
+
+		cluster := gocql.NewCluster(cfg.CassandraCluster...)
+		cluster.Keyspace = cfg.CassandraKeyspace
+		session, err := cluster.CreateSession()
+		if err != nil {
+			return backend{}, err
+		}
+		repo := database.InitRepository(session)
+		leases := database.InitLeaseStore(session)
+		ruleRepo := database.InitRuleRepository(session)
+		rateRepo := database.InitRateSnapshotRepository(session)
+		customerRepo := database.InitCustomerRepository(session)
+		numberRepo := database.InitAccountNumberRepository(session)
+		return backend{
+			accounts:  &repo,
+			customers: &customerRepo,
+			numbers:   &numberRepo,
+			leases:    &leases,
+			rules:     &ruleRepo,
+			rates:     &rateRepo,
+			close:     session.Close,
+		}, nil
+	}
+}
+
+func closeAll(closers ...io.Closer) {
+	for _, closer := range closers {
+		if err := closer.Close(); err != nil {
+			log.Println(err)
+		}
+	}
 }
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+
+	"github.com/thomaszub/go-es-example/migration"
+)
+
+func migrate(cfg Config, args []string) error {
+	flags := flag.NewFlagSet("migrate", flag.ExitOnError)
+	from := flags.String("from", cassandraEventStore, "event store to read the events from")
+	to := flags.String("to", fileEventStore, "event store to write the events to")
+	checkpointPath := flags.String("checkpoint", "migration.checkpoint", "file recording the already migrated accounts")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *from == *to {
+		return errors.New("source and target event store must differ")
+	}
+	for _, store := range []string{*from, *to} {
+		if err := cfg.LoadEventStore(store); err != nil {
+			return err
+		}
+	}
+
+	source, err := openBackend(cfg, *from)
+	if err != nil {
+		return err
+	}
+	defer source.close()
+	target, err := openBackend(cfg, *to)
+	if err != nil {
+		return err
+	}
+	defer target.close()
+	checkpoint, err := migration.OpenCheckpoint(*checkpointPath)
+	if err != nil {
+		return err
+	}
+	defer checkpoint.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	migrator := migration.NewMigrator(source.stores(), target.stores(), checkpoint)
+	result, err := migrator.Run(ctx)
+	log.Printf("Migrated %d rule events, %d rate snapshots and %d customers with %d events", result.RuleEvents, result.RateSnapshots, result.Customers, result.CustomerEvents)
+	log.Printf("Migrated %d accounts with %d events, skipped %d already migrated accounts", result.Migrated, result.Events, result.Skipped)
+	return err
+}
@@ -0,0 +1,68 @@
+package migration
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gocql/gocql"
+)
+
+type Checkpoint struct {
+	file     *os.File
+	migrated map[gocql.UUID]bool
+}
+
+func OpenCheckpoint(path string) (*Checkpoint, error) {
+	c := &Checkpoint{
+		migrated: map[gocql.UUID]bool{},
+	}
+	existing, err := os.Open(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	if err == nil {
+		defer existing.Close()
+		scanner := bufio.NewScanner(existing)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" {
+				continue
+			}
+			id, err := gocql.ParseUUID(line)
+			if err != nil {
+				return nil, fmt.Errorf("checkpoint %s contains invalid account id %s", path, line)
+			}
+			c.migrated[id] = true
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+	}
+	c.file, err = os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *Checkpoint) IsMigrated(accountId gocql.UUID) bool {
+	return c.migrated[accountId]
+}
+
+func (c *Checkpoint) MarkMigrated(accountId gocql.UUID) error {
+	if _, err := fmt.Fprintln(c.file, accountId.String()); err != nil {
+		return err
+	}
+	if err := c.file.Sync(); err != nil {
+		return err
+	}
+	c.migrated[accountId] = true
+	return nil
+}
+
+func (c *Checkpoint) Close() error {
+	return c.file.Close()
+}
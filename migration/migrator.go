@@ -0,0 +1,235 @@
+package migration
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/gocql/gocql"
+	"github.com/thomaszub/go-es-example/domain"
+	"github.com/thomaszub/go-es-example/fx"
+	"github.com/thomaszub/go-es-example/rules"
+)
+
+type Stores struct {
+	Accounts  domain.AccountEventRepository
+	Customers domain.CustomerEventRepository
+	Numbers   domain.AccountNumberRepository
+	Rules     rules.RuleEventRepository
+	Rates     fx.RateSnapshotRepository
+}
+
+type Migrator struct {
+	source     Stores
+	target     Stores
+	checkpoint *Checkpoint
+}
+
+type Result struct {
+	Migrated       int
+	Skipped        int
+	Events         int
+	Customers      int
+	CustomerEvents int
+	RuleEvents     int
+	RateSnapshots  int
+}
+
+func NewMigrator(source, target Stores, checkpoint *Checkpoint) Migrator {
+	return Migrator{
+		source:     source,
+		target:     target,
+		checkpoint: checkpoint,
+	}
+}
+
+func (m *Migrator) Run(ctx context.Context) (Result, error) {
+	result := Result{}
+	var err error
+	if result.RuleEvents, err = m.migrateRules(ctx); err != nil {
+		return result, fmt.Errorf("migrating rules failed: %w", err)
+	}
+	if result.RateSnapshots, err = m.migrateRates(ctx); err != nil {
+		return result, fmt.Errorf("migrating exchange rates failed: %w", err)
+	}
+	if err := m.migrateCustomers(ctx, &result); err != nil {
+		return result, err
+	}
+	ids, err := m.source.Accounts.ReadAllAccountIds(ctx)
+	if err != nil {
+		return result, err
+	}
+	for _, id := range ids {
+		if m.checkpoint.IsMigrated(id) {
+			result.Skipped++
+			continue
+		}
+		count, err := m.migrateAccount(ctx, id)
+		if err != nil {
+			return result, fmt.Errorf("migrating account %s failed: %w", id, err)
+		}
+		if err := m.checkpoint.MarkMigrated(id); err != nil {
+			return result, err
+		}
+		result.Migrated++
+		result.Events += count
+		log.Printf("Migrated account %s with %d events", id, count)
+	}
+	return result, nil
+}
+
+func (m *Migrator) migrateRules(ctx context.Context) (int, error) {
+	events, err := m.source.Rules.ReadAll(ctx)
+	if err != nil {
+		return 0, err
+	}
+	existing, err := m.target.Rules.ReadAll(ctx)
+	if err != nil {
+		return 0, err
+	}
+	migrated := map[gocql.UUID]bool{}
+	for _, event := range existing {
+		migrated[event.EventId] = true
+	}
+	count := 0
+	for _, event := range events {
+		if migrated[event.EventId] {
+			continue
+		}
+		if err := m.target.Rules.Write(ctx, event); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+func (m *Migrator) migrateRates(ctx context.Context) (int, error) {
+	snapshots, err := m.source.Rates.ReadAll(ctx)
+	if err != nil {
+		return 0, err
+	}
+	existing, err := m.target.Rates.ReadAll(ctx)
+	if err != nil {
+		return 0, err
+	}
+	migrated := map[gocql.UUID]bool{}
+	for _, snapshot := range existing {
+		migrated[snapshot.SnapshotId] = true
+	}
+	count := 0
+	for _, snapshot := range snapshots {
+		if migrated[snapshot.SnapshotId] {
+			continue
+		}
+		if err := m.target.Rates.Write(ctx, snapshot); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+func (m *Migrator) migrateCustomers(ctx context.Context, result *Result) error {
+	ids, err := m.source.Customers.ReadAllCustomerIds(ctx)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		count, err := m.migrateCustomer(ctx, id)
+		if err != nil {
+			return fmt.Errorf("migrating customer %s failed: %w", id, err)
+		}
+		if count > 0 {
+			result.Customers++
+			result.CustomerEvents += count
+			log.Printf("Migrated customer %s with %d events", id, count)
+		}
+	}
+	return nil
+}
+
+func (m *Migrator) migrateCustomer(ctx context.Context, customerId gocql.UUID) (int, error) {
+	events, err := m.source.Customers.ReadCustomerEvents(ctx, customerId)
+	if err != nil {
+		return 0, err
+	}
+	existing, err := m.target.Customers.ReadCustomerEvents(ctx, customerId)
+	if err != nil {
+		return 0, err
+	}
+	migrated := map[gocql.UUID]bool{}
+	for _, event := range existing {
+		migrated[event.GetEventId()] = true
+	}
+	var missing []domain.CustomerEvent
+	for _, event := range events {
+		if !migrated[event.GetEventId()] {
+			missing = append(missing, event)
+		}
+	}
+	if len(missing) == 0 {
+		return 0, nil
+	}
+	return len(missing), m.target.Customers.Write(ctx, missing...)
+}
+
+func (m *Migrator) migrateAccount(ctx context.Context, accountId gocql.UUID) (int, error) {
+	count := 0
+	err := domain.ForEachEvent(ctx, m.source.Accounts, accountId, domain.DefaultPageSize, func(event domain.AccountEvent) error {
+		count++
+		return m.target.Accounts.Write(ctx, event)
+	})
+	if err != nil {
+		return count, err
+	}
+	acc, err := m.verify(ctx, accountId)
+	if err != nil {
+		return count, err
+	}
+	return count, m.migrateAccountNumber(ctx, acc.AccountNumber(), accountId)
+}
+
+func (m *Migrator) migrateAccountNumber(ctx context.Context, number domain.AccountNumber, accountId gocql.UUID) error {
+	if number == "" {
+		return nil
+	}
+	registered, err := m.target.Numbers.Register(ctx, number, accountId)
+	if err != nil || registered {
+		return err
+	}
+	existing, _, err := m.target.Numbers.Lookup(ctx, number)
+	if err != nil {
+		return err
+	}
+	if existing != accountId {
+		return fmt.Errorf("account number %s is already registered for account %s in target", number, existing)
+	}
+	return nil
+}
+
+func (m *Migrator) verify(ctx context.Context, accountId gocql.UUID) (domain.Account, error) {
+	sourceService := domain.NewAccountService(m.source.Accounts, domain.AccountServiceConfig{})
+	targetService := domain.NewAccountService(m.target.Accounts, domain.AccountServiceConfig{})
+	expected, err := sourceService.LoadAccount(ctx, accountId)
+	if err != nil {
+		return expected, err
+	}
+	actual, err := targetService.LoadAccount(ctx, accountId)
+	if err != nil {
+		return expected, err
+	}
+	if expected.Balance() != actual.Balance() {
+		return expected, fmt.Errorf("balance %f in target does not match %f in source", actual.Balance(), expected.Balance())
+	}
+	if expected.Limit() != actual.Limit() {
+		return expected, fmt.Errorf("limit %f in target does not match %f in source", actual.Limit(), expected.Limit())
+	}
+	if expected.Currency() != actual.Currency() {
+		return expected, fmt.Errorf("currency %s in target does not match %s in source", actual.Currency(), expected.Currency())
+	}
+	if expected.State() != actual.State() {
+		return expected, fmt.Errorf("state %s in target does not match %s in source", actual.State(), expected.State())
+	}
+	return expected, nil
+}
@@ -0,0 +1,252 @@
+package rules
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/expr-lang/expr"
+	"github.com/expr-lang/expr/vm"
+	"github.com/gocql/gocql"
+	"github.com/thomaszub/go-es-example/domain"
+)
+
+type accountEnv struct {
+	Id               string  `expr:"id"`
+	State            string  `expr:"state"`
+	Currency         string  `expr:"currency"`
+	Balance          float64 `expr:"balance"`
+	AvailableBalance float64 `expr:"availableBalance"`
+	Limit            float64 `expr:"limit"`
+}
+
+type recentEnv struct {
+	WithdrawnLastDay    float64 `expr:"withdrawnLastDay"`
+	WithdrawalsLastHour int     `expr:"withdrawalsLastHour"`
+	DepositedLastDay    float64 `expr:"depositedLastDay"`
+	DepositsLastHour    int     `expr:"depositsLastHour"`
+}
+
+type ruleEnv struct {
+	Command string     `expr:"command"`
+	Amount  float64    `expr:"amount"`
+	Account accountEnv `expr:"account"`
+	Recent  recentEnv  `expr:"recent"`
+}
+
+type Engine struct {
+	repo     RuleEventRepository
+	mu       sync.Mutex
+	programs map[string]*vm.Program
+	rules    []Rule
+	loaded   bool
+}
+
+func NewEngine(repo RuleEventRepository) *Engine {
+	return &Engine{
+		repo:     repo,
+		programs: map[string]*vm.Program{},
+	}
+}
+
+func (e *Engine) Rules(ctx context.Context) ([]Rule, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if !e.loaded {
+		rules, err := e.loadRules(ctx)
+		if err != nil {
+			return nil, err
+		}
+		e.rules = rules
+		e.loaded = true
+	}
+	return append([]Rule(nil), e.rules...), nil
+}
+
+func (e *Engine) invalidate() {
+	e.rules = nil
+	e.loaded = false
+}
+
+func (e *Engine) loadRules(ctx context.Context) ([]Rule, error) {
+	events, err := e.repo.ReadAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	current := map[string]Rule{}
+	for _, event := range events {
+		switch event.Type {
+		case RuleDefinedEventType:
+			current[event.Name] = Rule{
+				Name:        event.Name,
+				Version:     event.Version,
+				Expression:  event.Expression,
+				Action:      event.Action,
+				Description: event.Description,
+				DefinedAt:   event.EventId,
+			}
+		case RuleRemovedEventType:
+			delete(current, event.Name)
+		default:
+			return nil, fmt.Errorf("%s is not a known rule event type", event.Type)
+		}
+	}
+	rules := make([]Rule, 0, len(current))
+	for _, rule := range current {
+		rules = append(rules, rule)
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		return rules[i].Name < rules[j].Name
+	})
+	return rules, nil
+}
+
+func (e *Engine) DefineRule(ctx context.Context, definition RuleDefinition) (Rule, error) {
+	if definition.Name == "" {
+		return Rule{}, domain.NewDomainError("a rule needs a name")
+	}
+	if definition.Action != domain.RuleDeny && definition.Action != domain.RuleReview {
+		return Rule{}, domain.NewDomainError("%s is not a valid rule action, use %s or %s", definition.Action, domain.RuleDeny, domain.RuleReview)
+	}
+	if _, err := compile(definition.Expression); err != nil {
+		return Rule{}, domain.NewDomainError("expression of rule %s is invalid: %s", definition.Name, err)
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	version, err := e.latestVersion(ctx, definition.Name)
+	if err != nil {
+		return Rule{}, err
+	}
+	event := RuleEvent{
+		EventId:     gocql.TimeUUID(),
+		Type:        RuleDefinedEventType,
+		Name:        definition.Name,
+		Version:     version + 1,
+		Expression:  definition.Expression,
+		Action:      definition.Action,
+		Description: definition.Description,
+	}
+	if err := e.repo.Write(ctx, event); err != nil {
+		return Rule{}, err
+	}
+	e.invalidate()
+	return Rule{
+		Name:        event.Name,
+		Version:     event.Version,
+		Expression:  event.Expression,
+		Action:      event.Action,
+		Description: event.Description,
+		DefinedAt:   event.EventId,
+	}, nil
+}
+
+func (e *Engine) RemoveRule(ctx context.Context, name string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	rules, err := e.loadRules(ctx)
+	if err != nil {
+		return err
+	}
+	for _, rule := range rules {
+		if rule.Name == name {
+			err := e.repo.Write(ctx, RuleEvent{
+				EventId: gocql.TimeUUID(),
+				Type:    RuleRemovedEventType,
+				Name:    name,
+				Version: rule.Version + 1,
+			})
+			if err != nil {
+				return err
+			}
+			e.invalidate()
+			return nil
+		}
+	}
+	return domain.NewDomainError("rule %s does not exist", name)
+}
+
+func (e *Engine) Evaluate(ctx context.Context, check domain.TransactionCheck) (domain.RuleDecision, error) {
+	decision := domain.RuleDecision{Outcome: domain.RuleAllow}
+	rules, err := e.Rules(ctx)
+	if err != nil {
+		return decision, err
+	}
+	env := toEnv(check)
+	for _, rule := range rules {
+		program, err := e.program(rule)
+		if err != nil {
+			return decision, err
+		}
+		result, err := vm.Run(program, env)
+		if err != nil {
+			return decision, fmt.Errorf("evaluating rule %s version %d failed: %w", rule.Name, rule.Version, err)
+		}
+		decision.Evaluated++
+		matched, _ := result.(bool)
+		if !matched || decision.Outcome == domain.RuleDeny {
+			continue
+		}
+		if rule.Action == domain.RuleDeny || decision.Outcome == domain.RuleAllow {
+			decision.Outcome = rule.Action
+			decision.Rule = rule.Name
+			decision.RuleVersion = rule.Version
+		}
+	}
+	return decision, nil
+}
+
+func (e *Engine) latestVersion(ctx context.Context, name string) (int, error) {
+	events, err := e.repo.ReadAll(ctx)
+	if err != nil {
+		return 0, err
+	}
+	version := 0
+	for _, event := range events {
+		if event.Name == name && event.Version > version {
+			version = event.Version
+		}
+	}
+	return version, nil
+}
+
+func (e *Engine) program(rule Rule) (*vm.Program, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	key := fmt.Sprintf("%s@%d", rule.Name, rule.Version)
+	if program, ok := e.programs[key]; ok {
+		return program, nil
+	}
+	program, err := compile(rule.Expression)
+	if err != nil {
+		return nil, fmt.Errorf("compiling rule %s version %d failed: %w", rule.Name, rule.Version, err)
+	}
+	e.programs[key] = program
+	return program, nil
+}
+
+func compile(expression string) (*vm.Program, error) {
+	return expr.Compile(expression, expr.Env(ruleEnv{}), expr.AsBool())
+}
+
+func toEnv(check domain.TransactionCheck) ruleEnv {
+	recent := check.Account.RecentActivity()
+	return ruleEnv{
+		Command: string(check.Command),
+		Amount:  check.Amount,
+		Account: accountEnv{
+			Id:               check.Account.AccountId().String(),
+			State:            string(check.Account.State()),
+			Currency:         string(check.Account.Currency()),
+			Balance:          check.Account.Balance(),
+			AvailableBalance: check.Account.AvailableBalance(),
+			Limit:            check.Account.Limit(),
+		},
+		Recent: recentEnv{
+			WithdrawnLastDay:    recent.WithdrawnLastDay,
+			WithdrawalsLastHour: recent.WithdrawalsLastHour,
+			DepositedLastDay:    recent.DepositedLastDay,
+			DepositsLastHour:    recent.DepositsLastHour,
+		},
+	}
+}
@@ -0,0 +1,90 @@
+package rules
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/thomaszub/go-es-example/domain"
+)
+
+type memoryRuleEventRepository struct {
+	mu     sync.Mutex
+	events []RuleEvent
+}
+
+func (r *memoryRuleEventRepository) Write(ctx context.Context, event RuleEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+	return nil
+}
+
+func (r *memoryRuleEventRepository) ReadAll(ctx context.Context) ([]RuleEvent, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]RuleEvent(nil), r.events...), nil
+}
+
+func TestDefineRuleAssignsDistinctVersionsConcurrently(t *testing.T) {
+	repo := &memoryRuleEventRepository{}
+	engine := NewEngine(repo)
+	const definitions = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, definitions)
+	for i := 0; i < definitions; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := engine.DefineRule(context.Background(), RuleDefinition{
+				Name:       "large-withdrawal",
+				Expression: "amount > 1000",
+				Action:     domain.RuleDeny,
+			})
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	events, _ := repo.ReadAll(context.Background())
+	versions := make([]int, 0, len(events))
+	for _, event := range events {
+		versions = append(versions, event.Version)
+	}
+	sort.Ints(versions)
+	for i, version := range versions {
+		if version != i+1 {
+			t.Fatalf("expected versions 1 to %d, got %v", definitions, versions)
+		}
+	}
+}
+
+func TestRemoveRuleIncrementsVersionAndInvalidatesRules(t *testing.T) {
+	ctx := context.Background()
+	engine := NewEngine(&memoryRuleEventRepository{})
+	if _, err := engine.DefineRule(ctx, RuleDefinition{Name: "night", Expression: "amount > 10", Action: domain.RuleReview}); err != nil {
+		t.Fatal(err)
+	}
+	if rules, _ := engine.Rules(ctx); len(rules) != 1 {
+		t.Fatalf("expected one rule, got %v", rules)
+	}
+	if err := engine.RemoveRule(ctx, "night"); err != nil {
+		t.Fatal(err)
+	}
+	if rules, _ := engine.Rules(ctx); len(rules) != 0 {
+		t.Fatalf("expected the rule to be removed, got %v", rules)
+	}
+	rule, err := engine.DefineRule(ctx, RuleDefinition{Name: "night", Expression: "amount > 20", Action: domain.RuleReview})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rule.Version != 3 {
+		t.Fatalf("expected version 3 after the removal, got %d", rule.Version)
+	}
+}
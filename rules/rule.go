@@ -0,0 +1,46 @@
+package rules
+
+import (
+	"context"
+
+	"github.com/gocql/gocql"
+	"github.com/thomaszub/go-es-example/domain"
+)
+
+type RuleEventType string
+
+const (
+	RuleDefinedEventType RuleEventType = "defined"
+	RuleRemovedEventType RuleEventType = "removed"
+)
+
+type RuleEvent struct {
+	EventId     gocql.UUID         `json:"eventId"`
+	Type        RuleEventType      `json:"type"`
+	Name        string             `json:"name"`
+	Version     int                `json:"version"`
+	Expression  string             `json:"expression,omitempty"`
+	Action      domain.RuleOutcome `json:"action,omitempty"`
+	Description string             `json:"description,omitempty"`
+}
+
+type RuleEventRepository interface {
+	Write(ctx context.Context, event RuleEvent) error
+	ReadAll(ctx context.Context) ([]RuleEvent, error)
+}
+
+type Rule struct {
+	Name        string
+	Version     int
+	Expression  string
+	Action      domain.RuleOutcome
+	Description string
+	DefinedAt   gocql.UUID
+}
+
+type RuleDefinition struct {
+	Name        string
+	Expression  string
+	Action      domain.RuleOutcome
+	Description string
+}